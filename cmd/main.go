@@ -27,6 +27,13 @@ func main() {
 	// Init Gemini client
 	gemini := infrastructure.NewGeminiClient()
 
+	// Blob storage untuk file original + signer untuk download URL
+	blobs := infrastructure.NewBlobStore()
+	signer := infrastructure.NewURLSigner()
+
+	// Mode redaksi PII default (job dengan blind review selalu blind)
+	redactionMode := infrastructure.LoadRedactionMode()
+
 	// Worker consumer → pakai Gemini evaluator
 	rmq.ConsumeJobs(func(job infrastructure.EvaluationJob) {
 		log.Printf("📥 Worker processing job: %+v\n", job)
@@ -55,6 +62,43 @@ func main() {
 				Update("status", "failed")
 			return
 		}
+		if upload.Status != "ready" {
+			log.Printf("❌ Upload %d is %s, text is not available for evaluation", upload.ID, upload.Status)
+			db.Model(&domain.Evaluation{}).
+				Where("id = ?", job.EvaluationID).
+				Update("status", "failed")
+			return
+		}
+
+		// Knockout rule job dicek dulu; kandidat yang gagal tidak perlu dievaluasi LLM
+		rejected, err := infrastructure.ApplyKnockoutRules(db, job.EvaluationID, jobMeta.ID, upload)
+		if err != nil {
+			log.Printf("❌ %v", err)
+			db.Model(&domain.Evaluation{}).
+				Where("id = ?", job.EvaluationID).
+				Update("status", "failed")
+			return
+		}
+		if rejected {
+			log.Printf("⛔ Evaluation %d rejected by knockout rule", job.EvaluationID)
+			return
+		}
+
+		// Instruksi ke model di teks kandidat: evaluasi tetap jalan tapi ditandai untuk dicek manual
+		injections, err := infrastructure.DetectCandidateInjection(db, upload)
+		if err != nil {
+			log.Printf("❌ %v", err)
+			db.Model(&domain.Evaluation{}).
+				Where("id = ?", job.EvaluationID).
+				Update("status", "failed")
+			return
+		}
+		if len(injections) > 0 {
+			log.Printf("⚠️ Evaluation %d flagged for review: %d suspicious span(s) in candidate documents", job.EvaluationID, len(injections))
+			if err := infrastructure.FlagInjection(db, job.EvaluationID, injections); err != nil {
+				log.Printf("❌ %v", err)
+			}
+		}
 
 		// ✅ DETAILED DEBUG LOGGING
 		log.Printf("=== 🐛 DEBUG DATA ===")
@@ -64,19 +108,76 @@ func main() {
 		log.Printf("---")
 		log.Printf("👤 Upload ID: %d", upload.ID)
 		log.Printf("📄 CV Text Length: %d characters", len(upload.CVText))
-		log.Printf("📄 CV Text Preview: %.200s", upload.CVText)
 		log.Printf("---")
 		log.Printf("🚀 Project Text Length: %d characters", len(upload.ProjectText))
-		log.Printf("🚀 Project Text Preview: %.200s", upload.ProjectText)
 		log.Printf("======================")
 
+		// Teks CV/project + link profil kandidat dari database, PII diredaksi sebelum dikirim ke model
+		mode := infrastructure.RedactionModeFor(jobMeta, redactionMode)
+		docs, mappingID, err := infrastructure.PrepareCandidateDocuments(db, upload, mode, "")
+		if err != nil {
+			log.Printf("❌ %v", err)
+			db.Model(&domain.Evaluation{}).
+				Where("id = ?", job.EvaluationID).
+				Update("status", "failed")
+			return
+		}
+		db.Model(&domain.Evaluation{}).
+			Where("id = ?", job.EvaluationID).
+			Updates(map[string]interface{}{
+				"redaction_mode":       mode,
+				"redaction_mapping_id": mappingID,
+			})
+		log.Printf("🔗 Links: %d, redaction: %s", len(docs.Links), mode)
+
+		// Template prompt evaluasi yang dipilih job (versi dicatat di evaluasi)
+		promptTemplate, err := infrastructure.ResolvePromptTemplate(db, jobMeta)
+		if err != nil {
+			log.Printf("❌ %v", err)
+			db.Model(&domain.Evaluation{}).
+				Where("id = ?", job.EvaluationID).
+				Update("status", "failed")
+			return
+		}
+		db.Model(&domain.Evaluation{}).
+			Where("id = ?", job.EvaluationID).
+			Update("prompt_template_id", promptTemplate.ID)
+		log.Printf("🧩 Prompt template: %s v%d", promptTemplate.Name, promptTemplate.Version)
+
+		// Isi prompt dipangkas sesuai budget token; bagian dokumen yang paling relevan didahulukan
+		input, promptReport, err := gemini.FitPrompt(infrastructure.PromptInput{
+			Description:       jobMeta.Description,
+			Rubric:            jobMeta.Rubric,
+			Candidates:        []infrastructure.CandidateDocuments{docs},
+			InstructionTokens: infrastructure.EvaluationInstructionTokens(promptTemplate),
+		})
+		if err != nil {
+			log.Printf("❌ Prompt for job %d does not fit: %v", job.EvaluationID, err)
+			db.Model(&domain.Evaluation{}).
+				Where("id = ?", job.EvaluationID).
+				Update("status", "failed")
+			return
+		}
+		reportBytes, _ := json.Marshal(promptReport)
+		reportStr := string(reportBytes)
+		db.Model(&domain.Evaluation{}).
+			Where("id = ?", job.EvaluationID).
+			Update("prompt_report", &reportStr)
+		if promptReport.Truncated {
+			log.Printf("✂️ Prompt for job %d truncated to ~%d of %d budget tokens", job.EvaluationID, promptReport.Tokens, promptReport.BudgetTokens)
+		}
+
+		prompt, err := infrastructure.RenderEvaluationPrompt(promptTemplate, jobMeta.Title, input)
+		if err != nil {
+			log.Printf("❌ %v", err)
+			db.Model(&domain.Evaluation{}).
+				Where("id = ?", job.EvaluationID).
+				Update("status", "failed")
+			return
+		}
+
 		// Panggil Gemini dengan data yang benar dari database
-		result, err := gemini.Evaluate(context.Background(),
-			jobMeta.Description,
-			jobMeta.Rubric,
-			upload.CVText,      // ✅ Data dari database
-			upload.ProjectText, // ✅ Data dari database
-		)
+		result, err := gemini.Evaluate(context.Background(), prompt)
 		if err != nil {
 			log.Printf("❌ Gemini evaluation error (job %d): %v", job.EvaluationID, err)
 			db.Model(&domain.Evaluation{}).
@@ -88,6 +189,15 @@ func main() {
 		// Log hasil mentah
 		log.Printf("🔎 Gemini raw result for job %d: %+v", job.EvaluationID, result)
 
+		// Model juga melaporkan teks yang mencoba memberi instruksi ke evaluator
+		if reported := infrastructure.ModelReportedInjections(result); len(reported) > 0 {
+			log.Printf("⚠️ Evaluation %d flagged for review: model reported %d suspicious instruction(s)", job.EvaluationID, len(reported))
+			injections = append(injections, reported...)
+			if err := infrastructure.FlagInjection(db, job.EvaluationID, injections); err != nil {
+				log.Printf("❌ %v", err)
+			}
+		}
+
 		// Simpan hasil
 		resultBytes, _ := json.Marshal(result)
 		resultStr := string(resultBytes)
@@ -104,6 +214,7 @@ func main() {
 		}
 
 		// Update evaluation dengan hasil
+		now := time.Now()
 		db.Model(&domain.Evaluation{}).
 			Where("id = ?", job.EvaluationID).
 			Updates(map[string]interface{}{
@@ -114,15 +225,22 @@ func main() {
 				"project_feedback": project["feedback"],
 				"overall_summary":  result["overall_summary"],
 				"result_json":      &resultStr,
-				"updated_at":       time.Now(),
+				"completed_at":     now,
+				"updated_at":       now,
 			})
 
 		log.Printf("✅ Worker finished job %d\n", job.EvaluationID)
 	})
 
+	// Worker consumer untuk pairwise tournament
+	rmq.ConsumeComparisons(interfaces.NewComparisonWorker(db, gemini))
+
+	// Worker consumer untuk ekstraksi teks dokumen yang baru di-upload
+	rmq.ConsumeExtractions(interfaces.NewExtractionWorker(db, gemini, blobs, rmq))
+
 	// Setup Gin router
 	router := gin.Default()
-	interfaces.NewHTTPHandler(router, db, rmq)
+	interfaces.NewHTTPHandler(router, db, rmq, blobs, signer)
 
 	log.Println("🚀 Server running on http://localhost:8080")
 	if err := router.Run(":8080"); err != nil {
@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Parameter BM25 standar (Okapi): K1 mengatur saturasi frekuensi term, B normalisasi
+// panjang dokumen
+const (
+	BM25K1 = 1.2
+	BM25B  = 0.75
+)
+
+// prefix term hasil normalisasi taxonomy, dibedakan dari kata biasa
+const skillTermPrefix = "skill:"
+
+// Kata umum (Inggris + Indonesia) yang tidak membedakan CV satu dengan yang lain
+var stopwords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`
+		a an and are as at be been but by for from has have in into is it its of on or our
+		that the their this to was were will with within you your we they he she his her
+		i me my us who what which when where how all any can could should would may must
+		not no than then also such using use used etc eg per via about over more most
+		dan atau yang di ke dari untuk dengan pada dalam adalah ini itu juga serta oleh
+		sebagai akan telah sudah bisa dapat para tersebut secara antara lain`) {
+		stopwords[w] = true
+	}
+}
+
+// Tokenize memecah teks jadi term untuk BM25: lowercase, dipisah di karakter selain huruf,
+// angka, + dan # (supaya "c++" dan "c#" tetap utuh), tanpa stopword dan term satu huruf
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+	})
+	terms := make([]string, 0, len(fields))
+	for _, f := range fields {
+		f = strings.TrimLeft(f, "+#")
+		if len([]rune(f)) < 2 || stopwords[f] {
+			continue
+		}
+		terms = append(terms, f)
+	}
+	return terms
+}
+
+// PrescreenTerms adalah term BM25 sebuah teks: kata-kata hasil Tokenize ditambah satu term
+// "skill:<nama kanonik>" untuk setiap skill taxonomy yang disebut, jadi "golang" di CV cocok
+// dengan "Go" di deskripsi job. Taxonomy boleh nil.
+func PrescreenTerms(text string, taxonomy *SkillTaxonomy) []string {
+	terms := Tokenize(text)
+	for _, name := range taxonomy.FindSkills(text) {
+		terms = append(terms, skillTermPrefix+NormalizeSkill(name))
+	}
+	return terms
+}
+
+// BM25Index menilai dokumen terhadap satu query. Dokumen ditambahkan satu per satu dan hanya
+// frekuensi term query + panjangnya yang disimpan, jadi teks aslinya tidak perlu ditahan.
+type BM25Index struct {
+	query    map[string]int // term → frekuensi di query
+	docs     []bm25Doc
+	df       map[string]int // term query → jumlah dokumen yang memuatnya
+	totalLen int
+}
+
+type bm25Doc struct {
+	id     uint
+	tf     map[string]int
+	length int
+}
+
+// BM25Score adalah skor satu dokumen. Matched adalah term query yang ada di dokumen,
+// diurutkan dari kontribusi terbesar.
+type BM25Score struct {
+	ID      uint
+	Score   float64
+	Matched []string
+}
+
+// NewBM25Index membuat index untuk term query
+func NewBM25Index(queryTerms []string) *BM25Index {
+	idx := &BM25Index{query: map[string]int{}, df: map[string]int{}}
+	for _, t := range queryTerms {
+		idx.query[t]++
+	}
+	return idx
+}
+
+// Add menambahkan satu dokumen
+func (idx *BM25Index) Add(id uint, terms []string) {
+	doc := bm25Doc{id: id, tf: map[string]int{}, length: len(terms)}
+	for _, t := range terms {
+		if _, ok := idx.query[t]; ok {
+			doc.tf[t]++
+		}
+	}
+	for t := range doc.tf {
+		idx.df[t]++
+	}
+	idx.docs = append(idx.docs, doc)
+	idx.totalLen += doc.length
+}
+
+// Rank menghitung skor BM25 semua dokumen, urut dari skor tertinggi (seri → ID terkecil).
+// IDF memakai varian ln(1 + (N - n + 0.5) / (n + 0.5)) supaya tidak pernah negatif.
+func (idx *BM25Index) Rank() []BM25Score {
+	n := float64(len(idx.docs))
+	if n == 0 {
+		return nil
+	}
+	avgLen := float64(idx.totalLen) / n
+	if avgLen == 0 {
+		avgLen = 1
+	}
+
+	scores := make([]BM25Score, 0, len(idx.docs))
+	for _, doc := range idx.docs {
+		type contribution struct {
+			term  string
+			score float64
+		}
+		var parts []contribution
+		for term, tf := range doc.tf {
+			df := float64(idx.df[term])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			f := float64(tf)
+			s := idf * f * (BM25K1 + 1) / (f + BM25K1*(1-BM25B+BM25B*float64(doc.length)/avgLen))
+			s *= float64(idx.query[term])
+			parts = append(parts, contribution{term, s})
+		}
+		sort.Slice(parts, func(i, j int) bool {
+			if parts[i].score != parts[j].score {
+				return parts[i].score > parts[j].score
+			}
+			return parts[i].term < parts[j].term
+		})
+		// Dijumlah setelah diurutkan supaya skor tidak bergantung urutan iterasi map
+		total := 0.0
+		matched := make([]string, 0, len(parts))
+		for _, p := range parts {
+			total += p.score
+			matched = append(matched, p.term)
+		}
+		scores = append(scores, BM25Score{ID: doc.id, Score: total, Matched: matched})
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].ID < scores[j].ID
+	})
+	return scores
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Candidate adalah data orang (terpisah dari dokumen yang di-upload).
+// Email disimpan dalam bentuk normalized supaya kandidat yang apply berkali-kali tetap satu baris.
+type Candidate struct {
+	ID        uint    `gorm:"primaryKey"`
+	Name      string  `gorm:"size:255"`
+	Email     *string `gorm:"size:255;uniqueIndex"` // NULL kalau kandidat tidak mengisi email
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NormalizeEmail → lowercase + trim, dipakai sebagai kunci deduplikasi kandidat
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// EmailOrEmpty mengembalikan email kandidat atau string kosong kalau NULL
+func (c *Candidate) EmailOrEmpty() string {
+	if c == nil || c.Email == nil {
+		return ""
+	}
+	return *c.Email
+}
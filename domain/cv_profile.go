@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// CVProfile adalah hasil parsing terstruktur CV sebuah upload: kontak dan ringkasan.
+// Posisi kerja, pendidikan, skill, sertifikasi dan bahasa disimpan di tabel masing-masing
+// dengan upload_id yang sama, jadi kandidat bisa difilter tanpa memanggil model lagi.
+type CVProfile struct {
+	ID        uint   `gorm:"primaryKey"`
+	UploadID  uint   `gorm:"not null;uniqueIndex"`
+	Status    string `gorm:"type:enum('parsed','failed');not null;index"`
+	Error     string `gorm:"type:text"` // alasan kalau status failed
+	FullName  string `gorm:"size:255"`
+	Email     string `gorm:"size:255;index"`
+	Phone     string `gorm:"size:64"`
+	Location  string `gorm:"size:255"`
+	Headline  string `gorm:"size:255"` // mis. "Senior Backend Engineer"
+	Summary   string `gorm:"type:text"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// CVPosition adalah satu posisi kerja di CV. Tanggal hanya sampai bulan; EndDate nil
+// dan Current true berarti masih bekerja di sana.
+type CVPosition struct {
+	ID          uint       `gorm:"primaryKey"`
+	UploadID    uint       `gorm:"not null;index"`
+	Title       string     `gorm:"size:255"`
+	Company     string     `gorm:"size:255;index"`
+	Location    string     `gorm:"size:255"`
+	StartDate   *time.Time `gorm:"type:date"`
+	EndDate     *time.Time `gorm:"type:date"`
+	Current     bool       `gorm:"not null;default:false"`
+	Description string     `gorm:"type:text"`
+	SortOrder   int        `gorm:"not null;default:0"` // urutan di CV
+}
+
+// CVEducation adalah satu riwayat pendidikan di CV
+type CVEducation struct {
+	ID          uint       `gorm:"primaryKey"`
+	UploadID    uint       `gorm:"not null;index"`
+	Institution string     `gorm:"size:255;index"`
+	Degree      string     `gorm:"size:128"` // mis. "S1", "Bachelor", "Master"
+	Field       string     `gorm:"size:255"` // jurusan
+	StartDate   *time.Time `gorm:"type:date"`
+	EndDate     *time.Time `gorm:"type:date"`
+	Grade       string     `gorm:"size:64"` // IPK / GPA apa adanya
+	SortOrder   int        `gorm:"not null;default:0"`
+}
+
+// CVSkill adalah satu skill di CV. NormalizedName (nama kanonik dari taxonomy kalau skill-nya
+// dikenal, mis. "Golang" → "go") dipakai untuk filter dan dedupe.
+type CVSkill struct {
+	ID             uint   `gorm:"primaryKey"`
+	UploadID       uint   `gorm:"not null;index"`
+	SkillID        *uint  `gorm:"index"`             // skill di taxonomy, nil kalau tidak dikenal
+	Name           string `gorm:"size:128;not null"` // seperti ditulis di CV
+	NormalizedName string `gorm:"size:128;not null;index"`
+	Category       string `gorm:"type:enum('technical','tool','soft','other');not null;default:'other'"`
+}
+
+// CVCertification adalah satu sertifikasi di CV
+type CVCertification struct {
+	ID        uint       `gorm:"primaryKey"`
+	UploadID  uint       `gorm:"not null;index"`
+	Name      string     `gorm:"size:255;not null;index"`
+	Issuer    string     `gorm:"size:255"`
+	IssuedAt  *time.Time `gorm:"type:date"`
+	ExpiresAt *time.Time `gorm:"type:date"`
+}
+
+// CVLanguage adalah bahasa yang dikuasai kandidat
+type CVLanguage struct {
+	ID          uint   `gorm:"primaryKey"`
+	UploadID    uint   `gorm:"not null;index"`
+	Language    string `gorm:"size:64;not null;index"`
+	Proficiency string `gorm:"size:64"` // mis. "native", "fluent", "B2"
+}
+
+// Kategori skill hasil parsing
+var SkillCategories = map[string]bool{"technical": true, "tool": true, "soft": true, "other": true}
+
+// NormalizeSkill menyamakan penulisan skill untuk filter: "  Go Lang " → "go lang"
+func NormalizeSkill(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Jenis link yang dikenali dari dokumen kandidat
+const (
+	LinkTypeGitHub        = "github"
+	LinkTypeGitLab        = "gitlab"
+	LinkTypeLinkedIn      = "linkedin"
+	LinkTypeStackOverflow = "stackoverflow"
+	LinkTypeEmail         = "email"
+	LinkTypeWebsite       = "website" // portfolio, blog, atau link lain
+)
+
+// Asal link: annotation PDF (link yang bisa diklik) atau URL yang tertulis di teks
+const (
+	LinkSourceAnnotation = "annotation"
+	LinkSourceText       = "text"
+)
+
+// DocumentLink adalah URL yang ditemukan di dokumen (CV / project) sebuah upload,
+// mis. profil GitHub, LinkedIn atau portfolio kandidat
+type DocumentLink struct {
+	ID        uint   `gorm:"primaryKey"`
+	UploadID  uint   `gorm:"not null;index"`
+	Kind      string `gorm:"type:enum('cv','project');not null"`
+	URL       string `gorm:"size:2048;not null"`
+	Type      string `gorm:"size:32;not null;index"`
+	Source    string `gorm:"type:enum('annotation','text');not null"`
+	Page      int    `gorm:"not null;default:0"` // 0 kalau tidak diketahui / bukan PDF
+	CreatedAt time.Time
+}
+
+// ClassifyLink menentukan jenis link dari host-nya
+func ClassifyLink(rawURL string) string {
+	if strings.HasPrefix(strings.ToLower(rawURL), "mailto:") {
+		return LinkTypeEmail
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return LinkTypeWebsite
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	switch {
+	case host == "github.com" || strings.HasSuffix(host, ".github.io"):
+		return LinkTypeGitHub
+	case host == "gitlab.com" || strings.HasSuffix(host, ".gitlab.io"):
+		return LinkTypeGitLab
+	case host == "linkedin.com" || strings.HasSuffix(host, ".linkedin.com"):
+		return LinkTypeLinkedIn
+	case host == "stackoverflow.com":
+		return LinkTypeStackOverflow
+	default:
+		return LinkTypeWebsite
+	}
+}
+
+// ProfileLinks memilih satu link per jenis (GitHub, LinkedIn, portfolio, ...). Link dari
+// CV diutamakan, lalu annotation diutamakan dibanding URL yang hanya tertulis di teks.
+func ProfileLinks(links []DocumentLink) map[string]string {
+	profiles := map[string]string{}
+	rank := map[string]int{}
+	for _, l := range links {
+		r := 0
+		if l.Kind == "cv" {
+			r += 2
+		}
+		if l.Source == LinkSourceAnnotation {
+			r++
+		}
+		if _, ok := profiles[l.Type]; !ok || r > rank[l.Type] {
+			profiles[l.Type] = l.URL
+			rank[l.Type] = r
+		}
+	}
+	return profiles
+}
@@ -6,13 +6,55 @@ type Evaluation struct {
 	ID              uint    `gorm:"primaryKey"`
 	UploadID        uint    `gorm:"not null"`
 	JobID           uint    `gorm:"not null"`
-	Status          string  `gorm:"type:enum('queued','processing','completed','failed');default:'queued'"`
+	Status          string  `gorm:"type:enum('queued','processing','completed','failed','rejected_by_rule');default:'queued'"`
 	CVMatchRate     float64 `gorm:"column:cv_match_rate"`
 	CVFeedback      string  `gorm:"type:text"`
 	ProjectScore    float64
 	ProjectFeedback string  `gorm:"type:text"`
 	OverallSummary  string  `gorm:"type:text"`
 	ResultJSON      *string `gorm:"type:json"` // pointer biar bisa NULL
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	RejectedRuleID  *uint   `gorm:"index"`     // knockout rule yang gagal (status rejected_by_rule)
+	KnockoutResult  *string `gorm:"type:json"` // JSON array KnockoutCheck, NULL kalau job tanpa rule
+
+	// Redaksi teks kandidat sebelum dikirim ke LLM + mapping placeholder yang dipakai
+	RedactionMode      string `gorm:"type:enum('none','pii','blind');not null;default:'none'"`
+	RedactionMappingID *uint
+
+	// Budget token prompt dan bagian job / rubric / dokumen yang dipangkas (JSON PromptReport)
+	PromptReport *string `gorm:"type:json"`
+	// Versi template prompt yang dipakai
+	PromptTemplateID *uint
+
+	// Teks kandidat yang mencoba memberi instruksi ke model → evaluasi dicek manual
+	NeedsReview    bool    `gorm:"not null;default:false;index"`
+	InjectionSpans *string `gorm:"type:json"` // JSON array InjectionSpan, NULL kalau tidak ada temuan
+	ReviewedAt     *time.Time
+	ReviewNote     string `gorm:"size:1024"`
+
+	// Waktu hasil model disimpan; updated_at ikut berubah saat evaluasi di-review
+	CompletedAt *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// EvaluatedAt: waktu evaluasi selesai. Evaluasi tanpa completed_at memakai created_at,
+// bukan updated_at yang ikut berubah saat di-review.
+func (e Evaluation) EvaluatedAt() time.Time {
+	if e.CompletedAt != nil {
+		return *e.CompletedAt
+	}
+	return e.CreatedAt
+}
+
+// Status yang valid untuk evaluasi (sesuai enum di kolom status)
+var evaluationStatuses = []string{"queued", "processing", "completed", "failed", "rejected_by_rule"}
+
+func IsValidEvaluationStatus(s string) bool {
+	for _, v := range evaluationStatuses {
+		if v == s {
+			return true
+		}
+	}
+	return false
 }
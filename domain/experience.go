@@ -0,0 +1,327 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ExperienceTimeline adalah hasil analisis riwayat kerja dari CV yang sudah di-parse:
+// total pengalaman, gap, posisi yang overlap dan tanggal yang tidak konsisten. Dihitung
+// deterministik di Go (bukan oleh model) saat CV di-parse. Angka yang tersimpan berlaku per
+// bulan analisis; pakai At untuk nilai per hari ini.
+type ExperienceTimeline struct {
+	ID            uint       `gorm:"primaryKey"`
+	UploadID      uint       `gorm:"not null;uniqueIndex"`
+	TotalMonths   int        `gorm:"not null;default:0;index"` // gabungan periode kerja, overlap tidak dihitung dua kali
+	PositionCount int        `gorm:"not null;default:0"`       // posisi dengan tanggal valid yang ikut dihitung
+	FirstStart    *time.Time `gorm:"type:date"`
+	LastEnd       *time.Time `gorm:"type:date"` // bulan analisis kalau masih bekerja
+	Current       bool       `gorm:"not null;default:false"`
+	Gaps          string     `gorm:"type:json"` // JSON array TimelineGap
+	Overlaps      string     `gorm:"type:json"` // JSON array TimelineOverlap
+	Issues        string     `gorm:"type:json"` // JSON array TimelineIssue
+	AnalyzedAt    time.Time  `gorm:"not null"`
+}
+
+// SkillExperience adalah lama pengalaman satu skill: gabungan periode posisi yang judul
+// atau deskripsinya menyebut skill tersebut (nama kanonik atau salah satu alias-nya)
+type SkillExperience struct {
+	ID        uint   `gorm:"primaryKey"`
+	UploadID  uint   `gorm:"not null;index"`
+	Skill     string `gorm:"size:128;not null;index"` // nama skill yang dinormalisasi
+	Name      string `gorm:"size:128;not null"`       // nama kanonik dari taxonomy, atau seperti di CV
+	Months    int    `gorm:"not null"`
+	Positions int    `gorm:"not null"`               // jumlah posisi yang menyebut skill ini
+	Current   bool   `gorm:"not null;default:false"` // disebut di posisi yang masih berjalan
+}
+
+// At mengembalikan timeline per bulan now. Posisi yang masih berjalan dihitung sampai bulan
+// analisis saja, jadi kalau kandidat masih bekerja bulan-bulan setelahnya ditambahkan di sini
+// supaya total pengalaman tidak membeku sejak CV di-parse.
+func (t ExperienceTimeline) At(now time.Time) ExperienceTimeline {
+	if elapsed := t.monthsSinceAnalysis(now); elapsed > 0 {
+		t.TotalMonths += elapsed
+		t.LastEnd = monthDate(monthIndex(*t.LastEnd) + elapsed)
+	}
+	return t
+}
+
+func (t ExperienceTimeline) monthsSinceAnalysis(now time.Time) int {
+	if !t.Current || t.LastEnd == nil {
+		return 0
+	}
+	return max(0, monthIndex(now)-monthIndex(*t.LastEnd))
+}
+
+// At: pengalaman skill per bulan now, bertambah seperti timeline-nya kalau skill disebut di
+// posisi yang masih berjalan
+func (s SkillExperience) At(t ExperienceTimeline, now time.Time) SkillExperience {
+	if s.Current {
+		s.Months += t.monthsSinceAnalysis(now)
+	}
+	return s
+}
+
+const (
+	// Jeda antar pekerjaan minimal sekian bulan baru dianggap gap
+	MinTimelineGapMonths = 3
+	// Overlap singkat (serah terima) tidak dilaporkan
+	MinTimelineOverlapMonths = 2
+)
+
+// Jenis masalah tanggal di riwayat kerja
+const (
+	TimelineIssueMissingStart  = "missing_start_date" // tidak dihitung
+	TimelineIssueMissingEnd    = "missing_end_date"   // bukan posisi saat ini tapi tanpa tanggal selesai → dihitung 1 bulan
+	TimelineIssueEndBeforeFrom = "end_before_start"   // tidak dihitung
+	TimelineIssueStartInFuture = "start_in_future"    // tidak dihitung
+	TimelineIssueEndInFuture   = "end_in_future"      // dipotong sampai bulan analisis
+	TimelineIssueImplausible   = "implausible_start"  // lebih dari 50 tahun lalu
+)
+
+// TimelineGap adalah jeda antar periode kerja (bulan From sampai To, inklusif)
+type TimelineGap struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Months int    `json:"months"`
+}
+
+// TimelineOverlap adalah dua posisi yang periodenya beririsan
+type TimelineOverlap struct {
+	First  string `json:"first"`
+	Second string `json:"second"`
+	Months int    `json:"months"`
+}
+
+// TimelineIssue adalah tanggal yang tidak konsisten di satu posisi
+type TimelineIssue struct {
+	Position string `json:"position"`
+	Issue    string `json:"issue"`
+	Detail   string `json:"detail"`
+}
+
+// ExperienceAnalysis adalah hasil AnalyzeExperience sebelum disimpan
+type ExperienceAnalysis struct {
+	TotalMonths   int
+	PositionCount int
+	FirstStart    *time.Time
+	LastEnd       *time.Time
+	Current       bool
+	Gaps          []TimelineGap
+	Overlaps      []TimelineOverlap
+	Issues        []TimelineIssue
+	Skills        []SkillExperience
+}
+
+// monthSpan adalah periode kerja dalam indeks bulan (tahun*12 + bulan), inklusif
+type monthSpan struct {
+	from, to int
+	current  bool
+	label    string
+	text     string // judul + deskripsi untuk mencari skill
+	lower    string // text lowercase, untuk skillMatcher
+}
+
+func monthIndex(t time.Time) int { return t.Year()*12 + int(t.Month()) - 1 }
+
+func monthLabel(idx int) string { return fmt.Sprintf("%04d-%02d", idx/12, idx%12+1) }
+
+func monthDate(idx int) *time.Time {
+	t := time.Date(idx/12, time.Month(idx%12+1), 1, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+// PositionLabel: "Backend Engineer at Acme"
+func PositionLabel(p CVPosition) string {
+	switch {
+	case p.Title != "" && p.Company != "":
+		return p.Title + " at " + p.Company
+	case p.Title != "":
+		return p.Title
+	default:
+		return p.Company
+	}
+}
+
+// AnalyzeExperience menghitung total pengalaman, pengalaman per skill, gap, overlap dan
+// masalah tanggal dari riwayat kerja. Granularitas bulan; bulan mulai dan selesai ikut
+// dihitung (Jan–Des 2020 = 12 bulan). Posisi yang masih berjalan dihitung sampai now.
+// Alias dari taxonomy (boleh nil) ikut dicari, jadi "golang" di deskripsi dihitung untuk "Go".
+func AnalyzeExperience(positions []CVPosition, skills []CVSkill, taxonomy *SkillTaxonomy, now time.Time) ExperienceAnalysis {
+	var a ExperienceAnalysis
+	nowIdx := monthIndex(now)
+
+	var spans []monthSpan
+	for _, p := range positions {
+		label := PositionLabel(p)
+		issue := func(kind, detail string) {
+			a.Issues = append(a.Issues, TimelineIssue{Position: label, Issue: kind, Detail: detail})
+		}
+
+		if p.StartDate == nil {
+			issue(TimelineIssueMissingStart, "start date is missing, position is not counted")
+			continue
+		}
+		from := monthIndex(*p.StartDate)
+		if from > nowIdx {
+			issue(TimelineIssueStartInFuture, fmt.Sprintf("starts in %s, position is not counted", monthLabel(from)))
+			continue
+		}
+		if now.Year()-p.StartDate.Year() > 50 {
+			issue(TimelineIssueImplausible, fmt.Sprintf("starts in %s", monthLabel(from)))
+		}
+
+		var to int
+		switch {
+		case p.Current:
+			to = nowIdx
+			a.Current = true
+		case p.EndDate == nil:
+			to = from
+			issue(TimelineIssueMissingEnd, "end date is missing and the position is not marked current, counted as one month")
+		default:
+			to = monthIndex(*p.EndDate)
+		}
+		if to < from {
+			issue(TimelineIssueEndBeforeFrom, fmt.Sprintf("ends %s before it starts %s, position is not counted", monthLabel(to), monthLabel(from)))
+			continue
+		}
+		if to > nowIdx {
+			issue(TimelineIssueEndInFuture, fmt.Sprintf("ends in %s, counted until %s", monthLabel(to), monthLabel(nowIdx)))
+			to = nowIdx
+		}
+
+		text := p.Title + "\n" + p.Description
+		spans = append(spans, monthSpan{
+			from:    from,
+			to:      to,
+			current: p.Current,
+			label:   label,
+			text:    text,
+			lower:   strings.ToLower(text),
+		})
+	}
+	a.PositionCount = len(spans)
+	if len(spans) == 0 {
+		return a
+	}
+
+	// Overlap antar pasangan posisi
+	for i := 0; i < len(spans); i++ {
+		for j := i + 1; j < len(spans); j++ {
+			months := min(spans[i].to, spans[j].to) - max(spans[i].from, spans[j].from) + 1
+			if months >= MinTimelineOverlapMonths {
+				a.Overlaps = append(a.Overlaps, TimelineOverlap{First: spans[i].label, Second: spans[j].label, Months: months})
+			}
+		}
+	}
+
+	merged := mergeSpans(spans)
+	for i, s := range merged {
+		a.TotalMonths += s.to - s.from + 1
+		if i > 0 {
+			if gap := s.from - merged[i-1].to - 1; gap >= MinTimelineGapMonths {
+				a.Gaps = append(a.Gaps, TimelineGap{From: monthLabel(merged[i-1].to + 1), To: monthLabel(s.from - 1), Months: gap})
+			}
+		}
+	}
+	a.FirstStart = monthDate(merged[0].from)
+	a.LastEnd = monthDate(merged[len(merged)-1].to)
+
+	// Pengalaman per skill = gabungan periode posisi yang menyebut skill tersebut
+	for _, skill := range skills {
+		matchers := taxonomy.mentionMatchers(skill.Name)
+		var mentioned []monthSpan
+		current := false
+		for _, s := range spans {
+			for _, m := range matchers {
+				if m.match(s.text, s.lower) {
+					mentioned = append(mentioned, s)
+					current = current || s.current
+					break
+				}
+			}
+		}
+		if len(mentioned) == 0 {
+			continue
+		}
+		months := 0
+		for _, s := range mergeSpans(mentioned) {
+			months += s.to - s.from + 1
+		}
+		name := taxonomy.Canonical(skill.Name)
+		a.Skills = append(a.Skills, SkillExperience{
+			Skill:     NormalizeSkill(name),
+			Name:      name,
+			Months:    months,
+			Positions: len(mentioned),
+			Current:   current,
+		})
+	}
+	sort.SliceStable(a.Skills, func(i, j int) bool { return a.Skills[i].Months > a.Skills[j].Months })
+	return a
+}
+
+// mergeSpans menggabungkan periode yang beririsan atau bersambung
+func mergeSpans(spans []monthSpan) []monthSpan {
+	sorted := append([]monthSpan(nil), spans...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].from < sorted[j].from })
+
+	var merged []monthSpan
+	for _, s := range sorted {
+		if n := len(merged); n > 0 && s.from <= merged[n-1].to+1 {
+			merged[n-1].to = max(merged[n-1].to, s.to)
+			continue
+		}
+		merged = append(merged, monthSpan{from: s.from, to: s.to})
+	}
+	return merged
+}
+
+// Nama skill yang juga kata umum bahasa Inggris ("on the go", "react quickly") atau sangat
+// pendek ("py", "ml") mudah salah cocok dengan teks biasa
+var ambiguousSkillNames = map[string]bool{
+	"go": true, "gin": true, "echo": true, "fiber": true, "flask": true, "express": true,
+	"rust": true, "ruby": true, "rails": true, "spring": true, "react": true, "swift": true,
+	"dart": true, "elm": true, "chef": true, "puppet": true, "ant": true, "hive": true,
+	"pig": true, "spark": true, "julia": true, "crystal": true,
+}
+
+func isAmbiguousSkillName(name string) bool {
+	if ambiguousSkillNames[name] {
+		return true
+	}
+	runes := []rune(name)
+	for _, r := range runes {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return len(runes) <= 2
+}
+
+// skillMentionPattern mencocokkan nama skill sebagai kata utuh (tidak peka huruf besar);
+// karakter seperti + # . dianggap bagian dari nama ("c" tidak cocok dengan "c++" atau "c#").
+// Nama yang ambigu hanya cocok kalau ditulis dengan huruf awal kapital ("Go", "GO") dan "-"
+// tidak dianggap batas kata, jadi "on the go", "go-live" dan "go-to-market" tidak dihitung.
+func skillMentionPattern(name string) *regexp.Regexp {
+	name = NormalizeSkill(name)
+	if name == "" {
+		return nil
+	}
+	if isAmbiguousSkillName(name) {
+		runes := []rune(name)
+		title := strings.ToUpper(string(runes[0])) + string(runes[1:])
+		return regexp.MustCompile(`(^|[^A-Za-z0-9+#-])(` + regexp.QuoteMeta(title) + `|` + regexp.QuoteMeta(strings.ToUpper(name)) + `)($|[^A-Za-z0-9+#-])`)
+	}
+	return regexp.MustCompile(`(?i)(^|[^a-z0-9+#])` + regexp.QuoteMeta(name) + `($|[^a-z0-9+#])`)
+}
+
+// Years: bulan → tahun dengan satu angka desimal
+func Years(months int) float64 {
+	return float64(months*10/12) / 10
+}
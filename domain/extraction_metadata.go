@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// ExtractionMetadata mencatat asal-usul teks satu dokumen (CV / project) dari sebuah upload:
+// extractor dan model yang dipakai, halaman yang gagal, durasi dan warning
+type ExtractionMetadata struct {
+	ID             uint    `gorm:"primaryKey"`
+	UploadID       uint    `gorm:"not null;index"`
+	StoredFileID   *uint   `gorm:"index"`
+	Kind           string  `gorm:"type:enum('cv','project');not null"`
+	Extractor      string  `gorm:"size:32;not null"`
+	Model          string  `gorm:"size:64"`
+	MimeType       string  `gorm:"size:127"`
+	PageCount      int     `gorm:"not null;default:0"`
+	EmptyPages     string  `gorm:"type:json"` // JSON array nomor halaman
+	FailedPages    string  `gorm:"type:json"` // JSON array nomor halaman
+	ImagePages     string  `gorm:"type:json"` // JSON array halaman yang dibaca dari gambar (scan)
+	CharacterCount int     `gorm:"not null"`
+	PrintableRatio float64 `gorm:"not null"`
+	DurationMS     int64   `gorm:"column:duration_ms;not null"`
+	Warnings       string  `gorm:"type:json"` // JSON array string
+	HiddenText     string  `gorm:"type:json"` // JSON array HiddenTextSpan (teks PDF putih / sangat kecil / di luar halaman)
+
+	// Document info dari file (saat ini hanya PDF)
+	DocumentTitle      string `gorm:"size:512"`
+	DocumentAuthor     string `gorm:"size:255"`
+	DocumentCreator    string `gorm:"size:255"` // aplikasi pembuat, mis. "Microsoft Word"
+	DocumentProducer   string `gorm:"size:255"` // library yang menghasilkan PDF
+	DocumentCreatedAt  *time.Time
+	DocumentModifiedAt *time.Time
+
+	// Metrik offline kalau project berupa source archive (JSON SourceAnalysis)
+	SourceAnalysis *string `gorm:"type:json"`
+
+	CreatedAt time.Time
+}
@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Sumber teks tempat instruksi mencurigakan ditemukan
+const (
+	InjectionSourceCV      = "cv"
+	InjectionSourceProject = "project"
+	InjectionSourceModel   = "model" // dilaporkan model sendiri saat evaluasi
+)
+
+// Jenis temuan
+const (
+	InjectionInstruction = "instruction" // kalimat yang memberi perintah ke evaluator / model
+	InjectionDelimiter   = "delimiter"   // meniru penanda prompt atau role chat
+	InjectionHiddenText  = "hidden_text" // teks PDF yang tidak terlihat pembaca
+)
+
+// Alasan teks PDF dianggap tersembunyi
+const (
+	HiddenWhiteText = "white_text" // warna teks (hampir) putih
+	HiddenTinyText  = "tiny_text"  // tinggi glyph di bawah ~2pt
+	HiddenOffPage   = "off_page"   // di luar area halaman
+)
+
+// Batas panjang teks tersembunyi (karakter non-spasi) yang dicatat. Teks putih di sidebar
+// gelap sering dipakai template CV untuk judul pendek, jadi ambangnya lebih tinggi.
+const (
+	MinHiddenTextChars = 20
+	MinWhiteTextChars  = 80
+)
+
+// HiddenTextSpan adalah potongan teks tersembunyi dari satu halaman PDF
+type HiddenTextSpan struct {
+	Page   int    `json:"page"`
+	Reason string `json:"reason"`
+	Text   string `json:"text"`
+}
+
+// InjectionSpan adalah satu potongan teks kandidat yang mencurigakan. Offset adalah posisi
+// byte di teks dokumen (-1 kalau tidak diketahui, mis. teks tersembunyi atau laporan model).
+type InjectionSpan struct {
+	Source string `json:"source"`
+	Kind   string `json:"kind"`
+	Rule   string `json:"rule"`
+	Text   string `json:"text"`
+	Offset int    `json:"offset"`
+	Page   int    `json:"page,omitempty"`
+}
+
+// maxInjectionSpans membatasi jumlah span yang disimpan per dokumen
+const maxInjectionSpans = 20
+
+type injectionRule struct {
+	name    string
+	kind    string
+	cvOnly  bool // project (laporan / source code proyek LLM) wajar menyebut field JSON dan markup chat
+	pattern *regexp.Regexp
+}
+
+// Pola sengaja cukup spesifik: kata seperti "ignore" atau "act as" sendiri wajar di CV
+var injectionRules = []injectionRule{
+	{"ignore_instructions", InjectionInstruction, false, regexp.MustCompile(`(?i)\b(?:ignore|disregard|forget|override|bypass)\b[^.\n]{0,40}?\b(?:previous|prior|above|earlier|preceding|all|any|your|the|these|system)\b[^.\n]{0,20}?\b(?:instructions?|prompts?|rules|guidelines|directions|criteria|rubric)\b`)},
+	{"ignore_instructions_id", InjectionInstruction, false, regexp.MustCompile(`(?i)\b(?:abaikan|lupakan|hiraukan)\b[^.\n]{0,40}?\b(?:instruksi|perintah|aturan|prompt|kriteria|rubrik)\b`)},
+	{"role_override", InjectionInstruction, false, regexp.MustCompile(`(?i)\b(?:you are now|from now on,? you|pretend (?:to be|you are)|your new (?:role|task|instructions?) (?:is|are))\b`)},
+	{"score_manipulation", InjectionInstruction, false, regexp.MustCompile(`(?i)\b(?:give|assign|award|rate|score|grade|rank)\b[^.\n]{0,30}?\b(?:this candidate|the candidate|this cv|this resume|me|him|her)\b[^.\n]{0,30}?(?:\b10\s*/\s*10\b|\b100\s*%|\b(?:perfect|full|maximum|max|highest|top)\b)`)},
+	{"score_manipulation_id", InjectionInstruction, false, regexp.MustCompile(`(?i)\bberi(?:kan)?\b[^.\n]{0,30}?\b(?:nilai|skor|score)\b[^.\n]{0,20}?(?:\b(?:sempurna|tertinggi|maksimal|penuh)\b|\b10\s*/\s*10\b|\b100\b)`)},
+	{"addressing_model", InjectionInstruction, false, regexp.MustCompile(`(?i)(?:\b(?:note|message|instructions?) (?:to|for) (?:the )?(?:ai|llm|model|evaluator|screener|ats|gpt|chatgpt|gemini)\b|\b(?:dear|hey|hi|attention) (?:ai|llm|chatgpt|gemini|gpt|model|evaluator)\b|\bif you are an? (?:ai|llm|language model|bot|automated)\b|\b(?:new|updated) instructions?\s*:|\binstruksi baru\b)`)},
+	{"output_fields", InjectionInstruction, true, regexp.MustCompile(`(?i)\b(?:match_rate|project_score|overall_summary)\b`)},
+	{"chat_markup", InjectionDelimiter, true, regexp.MustCompile(`(?i)(?:<\|?(?:im_start|im_end|endoftext|system|assistant)\|?>|</?(?:system|assistant|instructions?)>|\[/?(?:INST|SYS)\]|<<<|>>>|(?m:^\s*#{2,}\s*(?:system|instructions?)\b))`)},
+}
+
+// DetectInjection mencari instruksi yang ditujukan ke model di teks kandidat
+func DetectInjection(source, text string) []InjectionSpan {
+	var spans []InjectionSpan
+	for _, rule := range injectionRules {
+		if rule.cvOnly && source != InjectionSourceCV {
+			continue
+		}
+		for _, loc := range rule.pattern.FindAllStringIndex(text, -1) {
+			spans = append(spans, InjectionSpan{
+				Source: source,
+				Kind:   rule.kind,
+				Rule:   rule.name,
+				Text:   injectionContext(text, loc[0], loc[1]),
+				Offset: loc[0],
+			})
+		}
+	}
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].Offset < spans[j].Offset })
+	if len(spans) > maxInjectionSpans {
+		spans = spans[:maxInjectionSpans]
+	}
+	return spans
+}
+
+// HiddenTextInjections mengubah teks tersembunyi hasil ekstraksi PDF jadi temuan
+func HiddenTextInjections(source string, hidden []HiddenTextSpan) []InjectionSpan {
+	var spans []InjectionSpan
+	for _, h := range hidden {
+		spans = append(spans, InjectionSpan{
+			Source: source,
+			Kind:   InjectionHiddenText,
+			Rule:   h.Reason,
+			Text:   clipText(h.Text, 200),
+			Offset: -1,
+			Page:   h.Page,
+		})
+		if len(spans) == maxInjectionSpans {
+			break
+		}
+	}
+	return spans
+}
+
+// KeepHiddenText: teks tersembunyi cukup panjang untuk dicatat
+func KeepHiddenText(reason, text string) bool {
+	n := 0
+	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			n++
+		}
+	}
+	if reason == HiddenWhiteText {
+		return n >= MinWhiteTextChars
+	}
+	return n >= MinHiddenTextChars
+}
+
+// injectionContext: teks yang cocok plus sedikit konteks di baris yang sama
+func injectionContext(text string, start, end int) string {
+	from := start - 60
+	if from < 0 {
+		from = 0
+	}
+	to := end + 60
+	if to > len(text) {
+		to = len(text)
+	}
+	if i := strings.LastIndexByte(text[from:start], '\n'); i >= 0 {
+		from += i + 1
+	}
+	if i := strings.IndexByte(text[end:to], '\n'); i >= 0 {
+		to = end + i
+	}
+	// Jangan potong di tengah karakter UTF-8
+	for from > 0 && from < len(text) && !isRuneStart(text[from]) {
+		from--
+	}
+	for to < len(text) && !isRuneStart(text[to]) {
+		to++
+	}
+	return clipText(strings.TrimSpace(text[from:to]), 200)
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
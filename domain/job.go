@@ -8,5 +8,11 @@ type Job struct {
 	Title       string `gorm:"size:255;not null"`
 	Description string `gorm:"type:text;not null"`
 	Rubric      string `gorm:"type:json;not null"`
-	CreatedAt   time.Time
+	BlindReview bool   `gorm:"not null;default:false"` // redaksi atribut terlindungi + identitas sebelum evaluasi
+
+	// Template prompt evaluasi: nama kosong = "default", versi nil = versi terbaru
+	PromptTemplateName    string `gorm:"size:100;not null;default:''"`
+	PromptTemplateVersion *int
+
+	CreatedAt time.Time
 }
@@ -0,0 +1,224 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KnockoutRule adalah syarat wajib sebuah job yang dicek sebelum tahap LLM. Kandidat yang
+// tidak memenuhi satu rule aktif langsung mendapat status evaluasi rejected_by_rule.
+type KnockoutRule struct {
+	ID        uint   `gorm:"primaryKey"`
+	JobID     uint   `gorm:"not null;index"`
+	Name      string `gorm:"size:255;not null"` // mis. "Izin kerja di Indonesia"
+	Type      string `gorm:"type:enum('min_years','skill','certification','language','keyword','regex');not null"`
+	Value     string `gorm:"size:1024;not null"`
+	Negate    bool   `gorm:"not null;default:false"` // keyword / regex: gagal kalau justru ditemukan
+	Active    bool   `gorm:"not null;default:true"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Jenis knockout rule
+const (
+	KnockoutMinYears      = "min_years"     // Value: minimal tahun pengalaman (timeline CV)
+	KnockoutSkill         = "skill"         // Value: nama / alias skill taxonomy
+	KnockoutCertification = "certification" // Value: sebagian nama sertifikasi
+	KnockoutLanguage      = "language"      // Value: nama bahasa
+	KnockoutKeyword       = "keyword"       // Value: kata / frasa, alternatif dipisah "|"
+	KnockoutRegex         = "regex"         // Value: regular expression (RE2) terhadap teks CV
+)
+
+var KnockoutRuleTypes = []string{KnockoutMinYears, KnockoutSkill, KnockoutCertification, KnockoutLanguage, KnockoutKeyword, KnockoutRegex}
+
+// Hasil pengecekan satu rule. unknown = data tidak tersedia (mis. timeline belum ada),
+// tidak dianggap gagal supaya kandidat tidak tereliminasi karena parsing yang gagal.
+const (
+	KnockoutPassed  = "passed"
+	KnockoutFailed  = "failed"
+	KnockoutUnknown = "unknown"
+)
+
+// KnockoutCheck adalah hasil satu rule untuk satu upload
+type KnockoutCheck struct {
+	RuleID  uint   `json:"rule_id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Value   string `json:"value"`
+	Negate  bool   `json:"negate,omitempty"`
+	Outcome string `json:"outcome"`
+	Detail  string `json:"detail"`
+}
+
+// KnockoutFacts adalah data kandidat yang dipakai knockout rule: teks CV dan hasil parsing
+// CV terstruktur (kosong kalau CV belum / gagal di-parse)
+type KnockoutFacts struct {
+	CVText         string
+	TotalMonths    *int     // nil kalau belum ada timeline pengalaman
+	Skills         []string // nama skill yang sudah dinormalisasi
+	Certifications []string
+	Languages      []string
+}
+
+// ValidateKnockoutRule mengecek jenis dan nilai rule sebelum disimpan
+func ValidateKnockoutRule(r KnockoutRule) error {
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("name is required")
+	}
+	if strings.TrimSpace(r.Value) == "" {
+		return errors.New("value is required")
+	}
+	valid := false
+	for _, t := range KnockoutRuleTypes {
+		if t == r.Type {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid type: %s", r.Type)
+	}
+	if r.Negate && r.Type != KnockoutKeyword && r.Type != KnockoutRegex {
+		return errors.New("negate is only supported for keyword and regex rules")
+	}
+
+	switch r.Type {
+	case KnockoutMinYears:
+		if years, err := strconv.ParseFloat(strings.TrimSpace(r.Value), 64); err != nil || years < 0 {
+			return errors.New("min_years value must be a non-negative number")
+		}
+	case KnockoutRegex:
+		if _, err := regexp.Compile(r.Value); err != nil {
+			return fmt.Errorf("invalid regex: %v", err)
+		}
+	}
+	return nil
+}
+
+// EvaluateKnockout mengecek semua rule aktif secara berurutan. failed adalah rule pertama
+// yang gagal (nil kalau lolos semua); checks berisi hasil setiap rule. Skill, sertifikasi
+// dan bahasa dicari di hasil parsing CV dulu, lalu sebagai kata utuh di teks CV.
+func EvaluateKnockout(rules []KnockoutRule, facts KnockoutFacts, taxonomy *SkillTaxonomy) (checks []KnockoutCheck, failed *KnockoutCheck) {
+	for _, r := range rules {
+		if !r.Active {
+			continue
+		}
+		check := KnockoutCheck{RuleID: r.ID, Name: r.Name, Type: r.Type, Value: r.Value, Negate: r.Negate}
+		check.Outcome, check.Detail = evaluateKnockoutRule(r, facts, facts.CVText, taxonomy)
+		checks = append(checks, check)
+	}
+	for i := range checks {
+		if checks[i].Outcome == KnockoutFailed {
+			return checks, &checks[i]
+		}
+	}
+	return checks, nil
+}
+
+// UnknownKnockoutRules: rule yang tidak bisa dicek karena datanya tidak ada (mis. timeline
+// pengalaman tidak tersedia). Kandidat tidak ditolak, tapi evaluasinya perlu dicek manual.
+func UnknownKnockoutRules(checks []KnockoutCheck) []KnockoutCheck {
+	var unknown []KnockoutCheck
+	for _, c := range checks {
+		if c.Outcome == KnockoutUnknown {
+			unknown = append(unknown, c)
+		}
+	}
+	return unknown
+}
+
+func evaluateKnockoutRule(r KnockoutRule, facts KnockoutFacts, cvText string, taxonomy *SkillTaxonomy) (string, string) {
+	value := strings.TrimSpace(r.Value)
+	switch r.Type {
+	case KnockoutMinYears:
+		years, _ := strconv.ParseFloat(value, 64)
+		if facts.TotalMonths == nil {
+			return KnockoutUnknown, "experience timeline is not available"
+		}
+		required := int(math.Ceil(years * 12))
+		detail := fmt.Sprintf("%.1f years of experience, %s required", Years(*facts.TotalMonths), value)
+		if *facts.TotalMonths < required {
+			return KnockoutFailed, detail
+		}
+		return KnockoutPassed, detail
+
+	case KnockoutSkill:
+		name := taxonomy.Canonical(value)
+		want := NormalizeSkill(name)
+		for _, s := range facts.Skills {
+			if s == want {
+				return KnockoutPassed, name + " listed in CV skills"
+			}
+		}
+		lower := strings.ToLower(cvText)
+		for _, m := range taxonomy.mentionMatchers(value) {
+			if m.match(cvText, lower) {
+				return KnockoutPassed, name + " mentioned in CV text"
+			}
+		}
+		return KnockoutFailed, name + " not found in CV"
+
+	case KnockoutCertification, KnockoutLanguage:
+		records := facts.Certifications
+		if r.Type == KnockoutLanguage {
+			records = facts.Languages
+		}
+		want := strings.ToLower(value)
+		for _, rec := range records {
+			if strings.Contains(strings.ToLower(rec), want) {
+				return KnockoutPassed, rec + " listed in CV"
+			}
+		}
+		if p := skillMentionPattern(value); p != nil && p.MatchString(cvText) {
+			return KnockoutPassed, value + " mentioned in CV text"
+		}
+		return KnockoutFailed, value + " not found in CV"
+
+	case KnockoutKeyword:
+		for _, kw := range strings.Split(value, "|") {
+			if p := skillMentionPattern(kw); p != nil && p.MatchString(cvText) {
+				return textRuleOutcome(r.Negate, true, strings.TrimSpace(kw))
+			}
+		}
+		return textRuleOutcome(r.Negate, false, "")
+
+	case KnockoutRegex:
+		re, err := regexp.Compile(r.Value)
+		if err != nil {
+			return KnockoutUnknown, "invalid regex"
+		}
+		loc := re.FindStringIndex(facts.CVText)
+		if loc == nil {
+			return textRuleOutcome(r.Negate, false, "")
+		}
+		return textRuleOutcome(r.Negate, true, facts.CVText[loc[0]:loc[1]])
+	}
+	return KnockoutUnknown, "unsupported rule type"
+}
+
+// textRuleOutcome: keyword / regex lolos kalau ditemukan, atau kalau tidak ditemukan untuk
+// rule negate
+func textRuleOutcome(negate, matched bool, found string) (string, string) {
+	switch {
+	case matched && !negate:
+		return KnockoutPassed, fmt.Sprintf("found %q in CV text", clipText(found, 100))
+	case matched:
+		return KnockoutFailed, fmt.Sprintf("found %q in CV text", clipText(found, 100))
+	case negate:
+		return KnockoutPassed, "not found in CV text"
+	default:
+		return KnockoutFailed, "not found in CV text"
+	}
+}
+
+func clipText(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+)
+
+// PrescreenOptions adalah cutoff pre-screening: hanya TopN upload teratas dengan skor BM25
+// minimal MinScore yang lolos ke evaluasi LLM
+type PrescreenOptions struct {
+	TopN     int
+	MinScore float64
+}
+
+// PrescreenEntry adalah posisi satu upload di ranking pre-screening
+type PrescreenEntry struct {
+	Rank          int      `json:"rank"`
+	UploadID      uint     `json:"upload_id"`
+	Score         float64  `json:"score"`          // skor BM25 mentah
+	RelativeScore float64  `json:"relative_score"` // skor / skor tertinggi (0–1)
+	MatchedTerms  []string `json:"matched_terms"`  // term job yang ada di CV, kontribusi terbesar dulu
+	Selected      bool     `json:"selected"`       // lolos cutoff
+}
+
+// maksimal term yang ditampilkan per upload
+const maxPrescreenMatchedTerms = 15
+
+// PrescreenQueryText adalah teks query BM25 untuk job: deskripsi ditambah semua teks
+// kriteria di rubric (nilai string di JSON rubric, bobot dan nama parameter diabaikan)
+func PrescreenQueryText(job Job) string {
+	parts := []string{job.Description}
+	var rubric interface{}
+	if err := json.Unmarshal([]byte(job.Rubric), &rubric); err == nil {
+		collectStrings(rubric, &parts)
+	}
+	return strings.Join(parts, "\n")
+}
+
+func collectStrings(v interface{}, out *[]string) {
+	switch v := v.(type) {
+	case string:
+		*out = append(*out, v)
+	case []interface{}:
+		for _, item := range v {
+			collectStrings(item, out)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			collectStrings(v[k], out)
+		}
+	}
+}
+
+// SelectPrescreen mengubah skor BM25 (sudah urut) jadi ranking dan menandai upload yang
+// lolos cutoff. Upload tanpa satu pun term yang cocok (skor 0) tidak pernah lolos.
+func SelectPrescreen(scores []BM25Score, opts PrescreenOptions) []PrescreenEntry {
+	top := 0.0
+	if len(scores) > 0 {
+		top = scores[0].Score
+	}
+
+	entries := make([]PrescreenEntry, 0, len(scores))
+	selected := 0
+	for i, s := range scores {
+		e := PrescreenEntry{
+			Rank:         i + 1,
+			UploadID:     s.ID,
+			Score:        math.Round(s.Score*1000) / 1000,
+			MatchedTerms: s.Matched,
+		}
+		if top > 0 {
+			e.RelativeScore = math.Round(s.Score/top*1000) / 1000
+		}
+		if len(e.MatchedTerms) > maxPrescreenMatchedTerms {
+			e.MatchedTerms = e.MatchedTerms[:maxPrescreenMatchedTerms]
+		}
+		if s.Score > 0 && s.Score >= opts.MinScore && selected < opts.TopN {
+			e.Selected = true
+			selected++
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
@@ -0,0 +1,313 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Biaya penanda "[... N part(s) omitted ...]" dan potongan terkecil yang masih berguna
+const (
+	omissionMarkerTokens = 16
+	minPartialTokens     = 48
+	// Blok dokumen yang lebih besar dari ini dipecah per baris supaya seleksinya lebih halus
+	maxBlockTokens = 400
+)
+
+// EstimateTokens memperkirakan jumlah token teks untuk tokenizer Gemini: ~4 karakter per
+// token untuk aksara Latin, 1 token per karakter untuk aksara CJK, emoji dan sejenisnya
+func EstimateTokens(text string) int {
+	latin, wide := 0, 0
+	for _, r := range text {
+		if r < 0x2E80 {
+			latin++
+		} else {
+			wide++
+		}
+	}
+	return (latin+3)/4 + wide
+}
+
+// PromptSectionReport mencatat pemangkasan satu bagian prompt (job, rubric, CV, project, ...)
+type PromptSectionReport struct {
+	Section        string   `json:"section"`
+	OriginalTokens int      `json:"original_tokens"`
+	BudgetTokens   int      `json:"budget_tokens"`
+	Tokens         int      `json:"tokens"`
+	Truncated      bool     `json:"truncated"`
+	OmittedParts   int      `json:"omitted_parts,omitempty"` // paragraf / blok dokumen yang dibuang
+	Omitted        []string `json:"omitted,omitempty"`       // awal teks blok yang dibuang (maks 10)
+}
+
+// PromptReport adalah ringkasan budget token satu prompt
+type PromptReport struct {
+	BudgetTokens int                   `json:"budget_tokens"`
+	Tokens       int                   `json:"tokens"`
+	Truncated    bool                  `json:"truncated"`
+	Sections     []PromptSectionReport `json:"sections"`
+}
+
+// Add menambahkan laporan satu bagian
+func (r *PromptReport) Add(s PromptSectionReport) {
+	r.Sections = append(r.Sections, s)
+	r.Tokens += s.Tokens
+	r.Truncated = r.Truncated || s.Truncated
+}
+
+// BudgetRequest adalah kebutuhan token satu bagian prompt dan bobotnya saat budget kurang
+type BudgetRequest struct {
+	Tokens int
+	Weight float64
+}
+
+// AllocateTokenBudget membagi total token ke setiap bagian. Bagian yang kebutuhannya di
+// bawah jatahnya mendapat sesuai kebutuhan, sisanya dibagi ulang ke bagian lain sesuai bobot.
+func AllocateTokenBudget(total int, reqs []BudgetRequest) []int {
+	alloc := make([]int, len(reqs))
+	open := make([]bool, len(reqs))
+	for i, r := range reqs {
+		open[i] = r.Tokens > 0
+	}
+	remaining := total
+	for {
+		weight := 0.0
+		for i, r := range reqs {
+			if open[i] {
+				weight += r.Weight
+			}
+		}
+		if weight <= 0 || remaining <= 0 {
+			break
+		}
+		settled := false
+		for i, r := range reqs {
+			if open[i] && float64(r.Tokens) <= float64(remaining)*r.Weight/weight {
+				alloc[i] = r.Tokens
+				remaining -= r.Tokens
+				open[i] = false
+				settled = true
+			}
+		}
+		if settled {
+			continue
+		}
+		// Semua bagian yang tersisa melebihi jatahnya → dapat jatah proporsional
+		for i, r := range reqs {
+			if open[i] {
+				alloc[i] = int(float64(remaining) * r.Weight / weight)
+			}
+		}
+		break
+	}
+	return alloc
+}
+
+// FitText memotong teks dari belakang (di batas baris) supaya muat di budget
+func FitText(section, text string, budget int) (string, PromptSectionReport) {
+	report := PromptSectionReport{Section: section, OriginalTokens: EstimateTokens(text), BudgetTokens: budget}
+	if report.OriginalTokens <= budget {
+		report.Tokens = report.OriginalTokens
+		return text, report
+	}
+	out := TruncateTokens(text, budget)
+	report.Tokens = EstimateTokens(out)
+	report.Truncated = true
+	return out, report
+}
+
+// FitDocument memangkas dokumen kandidat supaya muat di budget. Dokumen dipecah jadi blok
+// (paragraf, atau per "=== ..." kalau ada banner seperti di source archive); blok pertama
+// (header / ringkasan) selalu dipertahankan, sisanya dipilih dari yang paling relevan dengan
+// query (BM25). Blok yang terpilih tetap ditampilkan sesuai urutan asli.
+func FitDocument(section, text string, budget int, query []string) (string, PromptSectionReport) {
+	report := PromptSectionReport{Section: section, OriginalTokens: EstimateTokens(text), BudgetTokens: budget}
+	if report.OriginalTokens <= budget {
+		report.Tokens = report.OriginalTokens
+		return text, report
+	}
+	report.Truncated = true
+
+	blocks := splitDocumentBlocks(text)
+	tokens := make([]int, len(blocks))
+	kept := make([]string, len(blocks))
+	idx := NewBM25Index(query)
+	for i, b := range blocks {
+		tokens[i] = EstimateTokens(b)
+		idx.Add(uint(i), Tokenize(b))
+	}
+
+	used := 0
+	take := func(i int, limit int) {
+		if tokens[i] <= limit {
+			kept[i] = blocks[i]
+		} else {
+			kept[i] = TruncateTokens(blocks[i], limit)
+		}
+		used += EstimateTokens(kept[i])
+	}
+	// Blok pertama biasanya nama, kontak dan ringkasan / README
+	take(0, budget-omissionMarkerTokens)
+
+	ranked := idx.Rank()
+	for _, s := range ranked {
+		i := int(s.ID)
+		if kept[i] == "" && used+tokens[i]+omissionMarkerTokens <= budget {
+			take(i, tokens[i])
+		}
+	}
+	// Sisa budget diisi potongan blok paling relevan yang belum masuk
+	if left := budget - used - 2*omissionMarkerTokens; left >= minPartialTokens {
+		for _, s := range ranked {
+			if i := int(s.ID); kept[i] == "" {
+				take(i, left)
+				break
+			}
+		}
+	}
+
+	out := joinKeptBlocks(blocks, kept)
+	// Penanda blok yang dibuang tidak ikut dihitung di atas: kalau ternyata melebihi budget,
+	// blok terpilih yang paling tidak relevan dibuang lagi
+	for j := len(ranked) - 1; j >= 0 && EstimateTokens(out) > budget; j-- {
+		if i := int(ranked[j].ID); i != 0 && kept[i] != "" {
+			kept[i] = ""
+			out = joinKeptBlocks(blocks, kept)
+		}
+	}
+
+	for i, b := range blocks {
+		if kept[i] == "" {
+			report.OmittedParts++
+			if len(report.Omitted) < 10 {
+				report.Omitted = append(report.Omitted, clipText(firstLine(b), 60))
+			}
+		}
+	}
+	report.Tokens = EstimateTokens(out)
+	return out, report
+}
+
+// joinKeptBlocks menyusun blok terpilih sesuai urutan asli; blok yang dibuang berurutan
+// diganti satu penanda
+func joinKeptBlocks(blocks, kept []string) string {
+	var parts []string
+	omitted := 0
+	flush := func() {
+		if omitted > 0 {
+			parts = append(parts, fmt.Sprintf("[... %d part(s) omitted to fit the prompt ...]", omitted))
+			omitted = 0
+		}
+	}
+	for i := range blocks {
+		if kept[i] == "" {
+			omitted++
+			continue
+		}
+		flush()
+		parts = append(parts, kept[i])
+	}
+	flush()
+	return strings.Join(parts, "\n\n")
+}
+
+// TruncateTokens memotong teks di batas karakter (bukan byte) supaya kira-kira muat di budget,
+// mundur ke akhir baris terakhir kalau tidak terlalu jauh
+func TruncateTokens(text string, budget int) string {
+	const marker = "\n[... truncated ...]"
+	limit := budget - omissionMarkerTokens
+	if limit <= 0 {
+		return ""
+	}
+	if EstimateTokens(text) <= budget {
+		return text
+	}
+
+	latin, wide, cut := 0, 0, 0
+	for i, r := range text {
+		if r < 0x2E80 {
+			latin++
+		} else {
+			wide++
+		}
+		if (latin+3)/4+wide > limit {
+			break
+		}
+		cut = i + utf8.RuneLen(r)
+	}
+	out := text[:cut]
+	if nl := strings.LastIndexByte(out, '\n'); nl > len(out)*4/5 {
+		out = out[:nl]
+	}
+	return strings.TrimRight(out, " \t\n") + marker
+}
+
+var (
+	bannerLine     = regexp.MustCompile(`(?m)^=== `)
+	paragraphBreak = regexp.MustCompile(`\n[ \t]*\n`)
+)
+
+// splitDocumentBlocks memecah dokumen jadi blok: per banner "=== ..." kalau ada, selain itu
+// per paragraf. Blok yang terlalu besar dipecah lagi per baris.
+func splitDocumentBlocks(text string) []string {
+	var raw []string
+	if locs := bannerLine.FindAllStringIndex(text, -1); len(locs) > 1 {
+		start := 0
+		for _, loc := range locs {
+			if loc[0] > start {
+				raw = append(raw, text[start:loc[0]])
+			}
+			start = loc[0]
+		}
+		raw = append(raw, text[start:])
+	} else {
+		raw = paragraphBreak.Split(text, -1)
+	}
+
+	var blocks []string
+	for _, b := range raw {
+		b = strings.Trim(b, "\n")
+		if strings.TrimSpace(b) == "" {
+			continue
+		}
+		blocks = append(blocks, splitLargeBlock(b)...)
+	}
+	if len(blocks) == 0 {
+		blocks = []string{text}
+	}
+	return blocks
+}
+
+func splitLargeBlock(b string) []string {
+	if EstimateTokens(b) <= maxBlockTokens {
+		return []string{b}
+	}
+	var chunks []string
+	var cur strings.Builder
+	curTokens := 0
+	for _, line := range strings.Split(b, "\n") {
+		t := EstimateTokens(line) + 1
+		if curTokens > 0 && curTokens+t > maxBlockTokens {
+			chunks = append(chunks, cur.String())
+			cur.Reset()
+			curTokens = 0
+		}
+		if curTokens > 0 {
+			cur.WriteByte('\n')
+		}
+		cur.WriteString(line)
+		curTokens += t
+	}
+	if curTokens > 0 {
+		chunks = append(chunks, cur.String())
+	}
+	return chunks
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultPromptTemplate dipakai job yang belum memilih template
+const DefaultPromptTemplate = "default"
+
+// PromptTemplate adalah satu versi template prompt evaluasi (Go text/template). Versi yang
+// sudah tersimpan tidak pernah diubah: edit membuat versi baru dengan nama yang sama, jadi
+// evaluasi lama tetap bisa ditelusuri ke prompt yang dipakai.
+type PromptTemplate struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"size:100;not null;uniqueIndex:idx_prompt_template_version"`
+	Version     int    `gorm:"not null;uniqueIndex:idx_prompt_template_version"`
+	Description string `gorm:"size:512"`
+	Body        string `gorm:"type:mediumtext;not null"`
+	CreatedAt   time.Time
+}
+
+// PromptTemplateData adalah data yang tersedia di template. CV, Project dan Links sudah
+// dibungkus penanda blok untrusted; Experience adalah fakta timeline yang dihitung sistem.
+type PromptTemplateData struct {
+	JobTitle    string
+	Description string
+	Rubric      string
+	CV          string
+	Project     string
+	Links       string
+	Experience  string
+}
+
+// Batas ukuran body template
+const maxPromptTemplateBytes = 64 * 1024
+
+var promptTemplateNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,99}$`)
+
+// ValidatePromptTemplateName: huruf kecil, angka, "-" dan "_", maks 100 karakter
+func ValidatePromptTemplateName(name string) error {
+	if !promptTemplateNamePattern.MatchString(name) {
+		return errors.New("name must be 1-100 characters of a-z, 0-9, '-' or '_' and start with a letter or digit")
+	}
+	return nil
+}
+
+// ParsePromptTemplate mem-parse body template. Field yang tidak ada di PromptTemplateData
+// baru ketahuan saat dieksekusi, jadi body juga dicoba dirender dengan data contoh; CV dan
+// Project wajib muncul di hasilnya supaya dokumen kandidat tidak hilang dari prompt.
+func ParsePromptTemplate(name, body string) (*template.Template, error) {
+	if strings.TrimSpace(body) == "" {
+		return nil, errors.New("body is required")
+	}
+	if len(body) > maxPromptTemplateBytes {
+		return nil, fmt.Errorf("body exceeds %d bytes", maxPromptTemplateBytes)
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	sample := PromptTemplateData{
+		JobTitle:    "{job_title}",
+		Description: "{description}",
+		Rubric:      "{rubric}",
+		CV:          "{cv_sample_block}",
+		Project:     "{project_sample_block}",
+		Links:       "{links}",
+		Experience:  "{experience}",
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, sample); err != nil {
+		return nil, err
+	}
+	for _, field := range []string{"CV", "Project"} {
+		if !strings.Contains(out.String(), "{"+strings.ToLower(field)+"_sample_block}") {
+			return nil, fmt.Errorf("template must include {{.%s}}", field)
+		}
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// RankingOptions mengatur bobot, threshold dan tie-breaking untuk leaderboard job
+type RankingOptions struct {
+	CVWeight        float64
+	ProjectWeight   float64
+	MinMatchRate    float64
+	MinProjectScore float64
+	MinScore        float64
+	TieBreak        string // "project_score" | "cv_match_rate" | "latest"
+}
+
+// RankingEntry adalah satu baris di leaderboard
+type RankingEntry struct {
+	Rank           int       `json:"rank"`
+	EvaluationID   uint      `json:"evaluation_id"`
+	UploadID       uint      `json:"upload_id"`
+	CandidateID    *uint     `json:"candidate_id"`
+	CandidateName  string    `json:"candidate_name"`
+	CandidateEmail string    `json:"candidate_email"`
+	CVMatchRate    float64   `json:"cv_match_rate"`
+	ProjectScore   float64   `json:"project_score"`
+	Score          float64   `json:"score"`
+	NeedsReview    bool      `json:"needs_review"` // teks kandidat mencurigakan, skor belum dicek manual
+	EvaluatedAt    time.Time `json:"evaluated_at"`
+}
+
+var RankingTieBreaks = []string{"project_score", "cv_match_rate", "latest"}
+
+// CombinedScore menggabungkan match rate (0-1) dan project score (1-10) jadi skor 0-1
+func (o RankingOptions) CombinedScore(matchRate, projectScore float64) float64 {
+	total := o.CVWeight + o.ProjectWeight
+	if total <= 0 {
+		return 0
+	}
+	return (o.CVWeight*matchRate + o.ProjectWeight*(projectScore/10)) / total
+}
+
+// RankEvaluations membuat leaderboard dari evaluasi completed beserta upload-nya.
+// Upload sebaiknya sudah di-preload dengan Candidate. Untuk kandidat yang dievaluasi
+// lebih dari sekali, hanya evaluasi terbaru yang dipakai.
+func RankEvaluations(evals []Evaluation, uploads map[uint]Upload, opts RankingOptions) []RankingEntry {
+	latest := map[string]RankingEntry{}
+
+	for _, e := range evals {
+		upload := uploads[e.UploadID]
+		key := candidateKey(upload)
+
+		entry := RankingEntry{
+			EvaluationID:   e.ID,
+			UploadID:       e.UploadID,
+			CandidateID:    upload.CandidateID,
+			CandidateName:  candidateName(upload),
+			CandidateEmail: upload.Candidate.EmailOrEmpty(),
+			CVMatchRate:    e.CVMatchRate,
+			ProjectScore:   e.ProjectScore,
+			Score:          opts.CombinedScore(e.CVMatchRate, e.ProjectScore),
+			NeedsReview:    e.NeedsReview,
+			EvaluatedAt:    e.EvaluatedAt(),
+		}
+
+		if prev, ok := latest[key]; ok && !isNewer(entry, prev) {
+			continue
+		}
+		latest[key] = entry
+	}
+
+	entries := make([]RankingEntry, 0, len(latest))
+	for _, entry := range latest {
+		if entry.CVMatchRate < opts.MinMatchRate ||
+			entry.ProjectScore < opts.MinProjectScore ||
+			entry.Score < opts.MinScore {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		switch opts.TieBreak {
+		case "cv_match_rate":
+			if a.CVMatchRate != b.CVMatchRate {
+				return a.CVMatchRate > b.CVMatchRate
+			}
+		case "latest":
+			if !a.EvaluatedAt.Equal(b.EvaluatedAt) {
+				return a.EvaluatedAt.After(b.EvaluatedAt)
+			}
+		default:
+			if a.ProjectScore != b.ProjectScore {
+				return a.ProjectScore > b.ProjectScore
+			}
+		}
+		// Fallback terakhir supaya urutan selalu deterministik
+		return a.EvaluationID < b.EvaluationID
+	})
+
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+	return entries
+}
+
+// candidateKey: candidate id kalau upload sudah terhubung ke kandidat, kalau tidak pakai upload id
+func candidateKey(u Upload) string {
+	if u.CandidateID != nil {
+		return "candidate:" + strconv.FormatUint(uint64(*u.CandidateID), 10)
+	}
+	return "upload:" + strconv.FormatUint(uint64(u.ID), 10)
+}
+
+func candidateName(u Upload) string {
+	if u.Candidate == nil {
+		return ""
+	}
+	return u.Candidate.Name
+}
+
+// isNewer: evaluasi yang selesai lebih akhir; kalau sama, ID yang lebih besar
+func isNewer(a, b RankingEntry) bool {
+	if !a.EvaluatedAt.Equal(b.EvaluatedAt) {
+		return a.EvaluatedAt.After(b.EvaluatedAt)
+	}
+	return a.EvaluationID > b.EvaluationID
+}
@@ -0,0 +1,357 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Mode redaksi teks kandidat sebelum dikirim ke LLM
+const (
+	RedactionNone  = "none"  // teks dikirim apa adanya
+	RedactionPII   = "pii"   // nama, kontak, alamat, tanggal lahir, nomor identitas, keterangan foto
+	RedactionBlind = "blind" // PII + atribut terlindungi (usia, gender, status pernikahan, agama, kewarganegaraan) + URL profil
+)
+
+// Jenis data yang diredaksi, dipakai sebagai nama placeholder: [EMAIL_1], [NAME_2], ...
+const (
+	RedactName          = "NAME"
+	RedactEmail         = "EMAIL"
+	RedactPhone         = "PHONE"
+	RedactAddress       = "ADDRESS"
+	RedactDateOfBirth   = "DATE_OF_BIRTH"
+	RedactIDNumber      = "ID_NUMBER"
+	RedactPhoto         = "PHOTO"
+	RedactAge           = "AGE"
+	RedactGender        = "GENDER"
+	RedactMaritalStatus = "MARITAL_STATUS"
+	RedactReligion      = "RELIGION"
+	RedactNationality   = "NATIONALITY"
+	RedactLink          = "LINK"
+)
+
+// RedactionMapping menyimpan pasangan placeholder → teks asli hasil redaksi sebuah upload,
+// supaya feedback model yang menyebut placeholder bisa dikembalikan ke teks asli di server.
+// Mapping tidak pernah ikut dikirim ke model dan tidak pernah diubah; kalau hasil redaksi
+// berubah (mis. CV di-parse ulang) dibuat baris baru, jadi evaluasi lama tetap cocok.
+type RedactionMapping struct {
+	ID        uint   `gorm:"primaryKey"`
+	UploadID  uint   `gorm:"not null;index"`
+	Mode      string `gorm:"type:enum('pii','blind');not null"`
+	Entries   string `gorm:"type:json;not null"` // JSON array RedactionEntry
+	CreatedAt time.Time
+}
+
+// RedactionEntry adalah satu nilai yang diganti placeholder
+type RedactionEntry struct {
+	Placeholder string `json:"placeholder"`
+	Kind        string `json:"kind"`
+	Original    string `json:"original"`
+}
+
+// IsValidRedactionMode: mode yang boleh dipakai
+func IsValidRedactionMode(mode string) bool {
+	return mode == RedactionNone || mode == RedactionPII || mode == RedactionBlind
+}
+
+var (
+	emailPattern = regexp.MustCompile(`(?i)\b[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}\b`)
+	// Nomor telepon diawali +kode negara, (0xx) atau 0; jumlah digit dicek terpisah supaya
+	// rentang tahun seperti "2019 - 2021" tidak ikut
+	phonePattern    = regexp.MustCompile(`(?:\+\d{1,3}|\(0\d{1,4}\)|\b0)[\d \t().\-]{7,18}\d`)
+	yearPattern     = regexp.MustCompile(`(?:^|\D)(?:19|20)\d{2}(?:\D|$)`)
+	nikPattern      = regexp.MustCompile(`\b\d{16}\b`) // NIK KTP
+	agePattern      = regexp.MustCompile(`(?i)\b\d{2}\s*(?:years?\s+old|y\.?o\.?|tahun\s+\(usia\))`)
+	bornPattern     = regexp.MustCompile(`(?i)\bborn\s+(?:on\s+)?((?:\d{1,2}[\s/.\-]+)?(?:[a-z]+|\d{1,2})[\s/.\-,]+\d{4})`)
+	photoLine       = regexp.MustCompile(`(?im)^[ \t]*[\[(]?(?:photo|foto|pas foto|profile (?:photo|picture)|headshot)\b[^\n]{0,80}$`)
+	urlPattern      = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>()"']+`)
+	placeholderLike = regexp.MustCompile(`^\[[A-Z_]+_\d+\]$`)
+)
+
+// URL dengan scheme / "www.", atau host (+ path) tanpa scheme: "linkedin.com/in/jane-doe", "janedoe.dev"
+var hostPathPattern = regexp.MustCompile(`(?i)\b(?:(?:https?://|www\.)[^\s<>()"']+|(?:[a-z0-9\-]+\.)+[a-z]{2,}(?:/[^\s<>()"']*)?)`)
+
+// labeledField adalah baris "Label: nilai"; nilainya diganti placeholder, labelnya tetap
+type labeledField struct {
+	kind    string
+	pattern *regexp.Regexp
+	blind   bool // hanya di mode blind
+}
+
+func labeled(kind string, blind bool, labels ...string) labeledField {
+	return labeledField{
+		kind:    kind,
+		blind:   blind,
+		pattern: regexp.MustCompile(`(?im)^([ \t\-•*]*(?:` + strings.Join(labels, "|") + `)[ \t]*[:：=\-–][ \t]*)([^\n]+)$`),
+	}
+}
+
+var labeledFields = []labeledField{
+	labeled(RedactDateOfBirth, false, `date of birth`, `birth ?date`, `d\.?o\.?b\.?`, `place,? date of birth`, `tempat,? tanggal lahir`, `tempat/tanggal lahir`, `tanggal lahir`, `tgl\.? lahir`, `ttl`),
+	labeled(RedactAddress, false, `address`, `home address`, `residential address`, `alamat`, `alamat rumah`, `domisili`),
+	labeled(RedactIDNumber, false, `nik`, `no\.? ?ktp`, `ktp`, `passport(?: no\.?| number)?`, `ssn`, `social security(?: number)?`, `national id(?: number)?`, `npwp`),
+	labeled(RedactAge, true, `age`, `umur`, `usia`),
+	labeled(RedactGender, true, `gender`, `sex`, `jenis kelamin`),
+	labeled(RedactMaritalStatus, true, `marital status`, `status pernikahan`, `status perkawinan`),
+	labeled(RedactReligion, true, `religion`, `agama`),
+	labeled(RedactNationality, true, `nationality`, `citizenship`, `kewarganegaraan`),
+}
+
+// Kata atribut terlindungi yang sering ditulis tanpa label, mis. baris "Male | Married | 28 years old"
+var protectedWords = map[string]string{
+	"male": RedactGender, "female": RedactGender, "pria": RedactGender, "wanita": RedactGender,
+	"laki-laki": RedactGender, "perempuan": RedactGender,
+	"married": RedactMaritalStatus, "single": RedactMaritalStatus, "divorced": RedactMaritalStatus,
+	"widowed": RedactMaritalStatus, "menikah": RedactMaritalStatus, "belum menikah": RedactMaritalStatus,
+	"lajang": RedactMaritalStatus, "cerai": RedactMaritalStatus,
+	"islam": RedactReligion, "muslim": RedactReligion, "christian": RedactReligion, "kristen": RedactReligion,
+	"catholic": RedactReligion, "katolik": RedactReligion, "protestan": RedactReligion, "hindu": RedactReligion,
+	"buddha": RedactReligion, "buddhist": RedactReligion, "konghucu": RedactReligion,
+	"wni": RedactNationality, "wna": RedactNationality,
+}
+
+var protectedSeparators = regexp.MustCompile(`[|,;•·/]`)
+
+// Redactor mengganti PII di teks dengan placeholder. Nilai yang sama selalu mendapat
+// placeholder yang sama, juga di antara CV dan project, jadi model tetap bisa merujuknya.
+type Redactor struct {
+	mode      string
+	prefix    string // mis. "A_" → [A_NAME_1]; kosong untuk satu kandidat
+	names     []*regexp.Regexp
+	nameWords []string          // kata nama (lowercase) untuk dicari di URL profil
+	byValue   map[string]string // kind + nilai (lowercase) → placeholder
+	counter   map[string]int
+	entries   []RedactionEntry
+}
+
+// NewRedactor membuat redactor untuk mode pii / blind. names adalah nama kandidat yang
+// diketahui (dari form upload dan hasil parsing CV); nama lengkap dan setiap kata nama
+// (minimal 3 huruf, diawali huruf besar di teks) diganti [NAME_n].
+func NewRedactor(mode string, names []string) *Redactor {
+	r := &Redactor{mode: mode, byValue: map[string]string{}, counter: map[string]int{}}
+
+	seen := map[string]bool{}
+	var full, parts []string
+	for _, n := range names {
+		words := strings.Fields(n)
+		if len(words) == 0 || seen[strings.ToLower(strings.Join(words, " "))] {
+			continue
+		}
+		seen[strings.ToLower(strings.Join(words, " "))] = true
+		if len(words) > 1 {
+			full = append(full, strings.Join(words, " "))
+		}
+		for _, w := range words {
+			w = strings.Trim(w, ".,")
+			if len([]rune(w)) >= 3 && !seen["word:"+strings.ToLower(w)] {
+				seen["word:"+strings.ToLower(w)] = true
+				parts = append(parts, w)
+			}
+		}
+	}
+	// Nama lengkap dulu, lalu kata yang lebih panjang dulu
+	longestFirst := func(list []string) func(i, j int) bool {
+		return func(i, j int) bool {
+			if len(list[i]) != len(list[j]) {
+				return len(list[i]) > len(list[j])
+			}
+			return list[i] < list[j]
+		}
+	}
+	sort.Slice(full, longestFirst(full))
+	sort.Slice(parts, longestFirst(parts))
+	for _, p := range parts {
+		r.nameWords = append(r.nameWords, strings.ToLower(p))
+	}
+	for _, f := range full {
+		words := strings.Fields(f)
+		for i := range words {
+			words[i] = regexp.QuoteMeta(words[i])
+		}
+		r.names = append(r.names, regexp.MustCompile(`(?i)\b`+strings.Join(words, `\s+`)+`\b`))
+	}
+	for _, p := range parts {
+		// Kata nama saja hanya kalau diawali huruf besar, supaya kata umum tidak ikut
+		first := []rune(p)[0]
+		rest := string([]rune(p)[1:])
+		r.names = append(r.names, regexp.MustCompile(`\b[`+regexp.QuoteMeta(strings.ToUpper(string(first)))+`]`+`(?i:`+regexp.QuoteMeta(rest)+`)\b`))
+	}
+	return r
+}
+
+// Redact mengganti PII (dan di mode blind, atribut terlindungi + URL) di teks
+func (r *Redactor) Redact(text string) string {
+	if r == nil || r.mode == RedactionNone || text == "" {
+		return text
+	}
+	blind := r.mode == RedactionBlind
+
+	for _, f := range labeledFields {
+		if f.blind && !blind {
+			continue
+		}
+		text = f.pattern.ReplaceAllStringFunc(text, func(m string) string {
+			sub := f.pattern.FindStringSubmatch(m)
+			value := strings.TrimSpace(sub[2])
+			if value == "" || placeholderLike.MatchString(value) {
+				return m
+			}
+			return sub[1] + r.placeholder(f.kind, value)
+		})
+	}
+
+	text = photoLine.ReplaceAllStringFunc(text, func(m string) string {
+		return r.placeholder(RedactPhoto, strings.TrimSpace(m))
+	})
+	text = emailPattern.ReplaceAllStringFunc(text, func(m string) string { return r.placeholder(RedactEmail, m) })
+	if blind {
+		text = urlPattern.ReplaceAllStringFunc(text, func(m string) string {
+			return r.placeholder(RedactLink, strings.TrimRight(m, ".,;:"))
+		})
+	} else {
+		// Mode pii: URL profil biasanya memuat nama kandidat dalam bentuk slug
+		// ("linkedin.com/in/jane-doe", "github.com/janedoe"), pola nama biasa tidak menangkapnya
+		text = hostPathPattern.ReplaceAllStringFunc(text, func(m string) string {
+			link := strings.TrimRight(m, ".,;:")
+			if !r.containsName(link) {
+				return m
+			}
+			return r.placeholder(RedactLink, link) + m[len(link):]
+		})
+	}
+	text = phonePattern.ReplaceAllStringFunc(text, func(m string) string {
+		digits := 0
+		for _, c := range m {
+			if c >= '0' && c <= '9' {
+				digits++
+			}
+		}
+		// "05-2019 - 06-2021" adalah rentang tanggal, bukan nomor telepon
+		if digits < 9 || digits > 15 || len(yearPattern.FindAllString(m, -1)) >= 2 {
+			return m
+		}
+		return r.placeholder(RedactPhone, strings.TrimSpace(m))
+	})
+	text = nikPattern.ReplaceAllStringFunc(text, func(m string) string { return r.placeholder(RedactIDNumber, m) })
+	text = bornPattern.ReplaceAllStringFunc(text, func(m string) string {
+		sub := bornPattern.FindStringSubmatch(m)
+		return strings.TrimSuffix(m, sub[1]) + r.placeholder(RedactDateOfBirth, sub[1])
+	})
+	for _, p := range r.names {
+		text = p.ReplaceAllStringFunc(text, func(m string) string { return r.placeholder(RedactName, m) })
+	}
+
+	if blind {
+		text = agePattern.ReplaceAllStringFunc(text, func(m string) string { return r.placeholder(RedactAge, m) })
+		text = r.redactProtectedLines(text)
+	}
+	return text
+}
+
+// containsName: URL memuat salah satu kata nama kandidat (tanpa memperhatikan huruf besar,
+// "-", "_" atau ".") di host atau path-nya
+func (r *Redactor) containsName(link string) bool {
+	lower := strings.ToLower(link)
+	for _, prefix := range []string{"https://", "http://", "www."} {
+		lower = strings.TrimPrefix(lower, prefix)
+	}
+	for _, w := range r.nameWords {
+		if strings.Contains(lower, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactProtectedLines: baris pendek yang seluruh isinya atribut terlindungi
+// ("Male, Married, Islam"), atau nilai setelah label pendek yang tidak dikenal
+// ("Status: Menikah"), diganti placeholder per bagian
+func (r *Redactor) redactProtectedLines(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		prefix, body := "", line
+		if idx := strings.Index(line, ":"); idx > 0 && idx <= 30 {
+			prefix, body = line[:idx+1], line[idx+1:]
+		}
+		trimmed := strings.TrimSpace(body)
+		if trimmed == "" || len(trimmed) > 80 {
+			continue
+		}
+		parts := protectedSeparators.Split(trimmed, -1)
+		kinds := make([]string, len(parts))
+		all := true
+		for j, p := range parts {
+			p = strings.TrimSpace(p)
+			if kind, ok := protectedWords[strings.ToLower(p)]; ok {
+				kinds[j] = kind
+			} else if !placeholderLike.MatchString(p) {
+				all = false
+				break
+			}
+		}
+		if !all || !containsNonEmpty(kinds) {
+			continue
+		}
+		out := body
+		for j, p := range parts {
+			if kinds[j] != "" {
+				p = strings.TrimSpace(p)
+				out = strings.Replace(out, p, r.placeholder(kinds[j], p), 1)
+			}
+		}
+		lines[i] = prefix + out
+	}
+	return strings.Join(lines, "\n")
+}
+
+func containsNonEmpty(values []string) bool {
+	for _, v := range values {
+		if v != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redactor) placeholder(kind, original string) string {
+	key := kind + "\x00" + strings.ToLower(original)
+	if p, ok := r.byValue[key]; ok {
+		return p
+	}
+	r.counter[kind]++
+	p := fmt.Sprintf("[%s%s_%d]", r.prefix, kind, r.counter[kind])
+	r.byValue[key] = p
+	r.entries = append(r.entries, RedactionEntry{Placeholder: p, Kind: kind, Original: original})
+	return p
+}
+
+// WithPrefix memberi awalan ke placeholder ("A" → [A_NAME_1]) supaya dua kandidat yang
+// diredaksi terpisah tidak berbagi placeholder di satu prompt. Dipanggil sebelum Redact.
+func (r *Redactor) WithPrefix(prefix string) *Redactor {
+	if prefix != "" {
+		r.prefix = prefix + "_"
+	}
+	return r
+}
+
+// Entries mengembalikan mapping placeholder → teks asli, urut sesuai kemunculan
+func (r *Redactor) Entries() []RedactionEntry {
+	if r == nil {
+		return nil
+	}
+	return r.entries
+}
+
+// Unredact mengembalikan placeholder di teks (mis. feedback model) ke teks aslinya
+func Unredact(text string, entries []RedactionEntry) string {
+	if len(entries) == 0 || text == "" {
+		return text
+	}
+	pairs := make([]string, 0, len(entries)*2)
+	for _, e := range entries {
+		pairs = append(pairs, e.Placeholder, e.Original)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
@@ -0,0 +1,257 @@
+package domain
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Skill adalah satu skill kanonik di taxonomy, mis. "Go" dengan alias "golang" / "go lang".
+// Category adalah kategori induk (mis. "Programming Language"), Parent skill induk
+// (mis. framework "Gin" → "Go").
+type Skill struct {
+	ID        uint         `gorm:"primaryKey"`
+	Name      string       `gorm:"size:128;not null;uniqueIndex"`
+	MatchKey  string       `gorm:"size:128;not null;uniqueIndex"` // SkillKey(Name)
+	Category  string       `gorm:"size:128;not null;index"`
+	ParentID  *uint        `gorm:"index"`
+	Parent    *Skill       `gorm:"constraint:OnDelete:SET NULL"`
+	Aliases   []SkillAlias `gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// SkillAlias adalah penulisan lain sebuah skill. Key unik di seluruh taxonomy, jadi satu
+// alias tidak bisa menunjuk ke dua skill.
+type SkillAlias struct {
+	ID       uint   `gorm:"primaryKey"`
+	SkillID  uint   `gorm:"not null;index"`
+	Alias    string `gorm:"size:128;not null"`
+	MatchKey string `gorm:"size:128;not null;uniqueIndex"` // SkillKey(Alias)
+}
+
+// SkillKey adalah kunci pencocokan skill: lowercase tanpa spasi, titik, tanda hubung dan
+// underscore, jadi "Go-Lang", "go lang" dan "golang" sama. + dan # tetap dipertahankan
+// supaya "C", "C++" dan "C#" berbeda.
+func SkillKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch r {
+		case ' ', '\t', '.', '-', '_':
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// SkillTaxonomy adalah index taxonomy di memory untuk normalisasi skill
+type SkillTaxonomy struct {
+	skills   map[uint]*Skill
+	byKey    map[string]*Skill
+	patterns map[uint][]skillMatcher // pola nama + alias per skill, di-compile sekali per taxonomy
+}
+
+// skillMatcher adalah pola kata utuh satu nama / alias. literal (lowercase) dicek dulu dengan
+// strings.Contains supaya regex hanya dijalankan untuk nama yang memang muncul di teks.
+type skillMatcher struct {
+	literal string
+	pattern *regexp.Regexp
+}
+
+func (m skillMatcher) match(text, lower string) bool {
+	return strings.Contains(lower, m.literal) && m.pattern.MatchString(text)
+}
+
+// NewSkillTaxonomy membangun index dari skill (beserta Aliases-nya) yang dimuat dari database
+func NewSkillTaxonomy(skills []Skill) *SkillTaxonomy {
+	t := &SkillTaxonomy{skills: map[uint]*Skill{}, byKey: map[string]*Skill{}, patterns: map[uint][]skillMatcher{}}
+	for i := range skills {
+		s := &skills[i]
+		t.skills[s.ID] = s
+		t.byKey[SkillKey(s.Name)] = s
+	}
+	for i := range skills {
+		for _, a := range skills[i].Aliases {
+			if _, exists := t.byKey[a.MatchKey]; !exists {
+				t.byKey[a.MatchKey] = &skills[i]
+			}
+		}
+	}
+	for _, s := range t.skills {
+		t.patterns[s.ID] = compileSkillMatchers(t.Names(s.Name))
+	}
+	return t
+}
+
+// Lookup mencari skill kanonik dari nama atau alias. Taxonomy nil selalu tidak ketemu.
+func (t *SkillTaxonomy) Lookup(name string) (*Skill, bool) {
+	if t == nil {
+		return nil, false
+	}
+	s, ok := t.byKey[SkillKey(name)]
+	return s, ok
+}
+
+// Canonical mengembalikan nama kanonik skill; kalau tidak ada di taxonomy, nama aslinya
+func (t *SkillTaxonomy) Canonical(name string) string {
+	if s, ok := t.Lookup(name); ok {
+		return s.Name
+	}
+	return strings.TrimSpace(name)
+}
+
+// Names mengembalikan nama kanonik + semua alias sebuah skill (untuk mencari di teks)
+func (t *SkillTaxonomy) Names(name string) []string {
+	s, ok := t.Lookup(name)
+	if !ok {
+		return []string{name}
+	}
+	names := []string{s.Name}
+	for _, a := range s.Aliases {
+		names = append(names, a.Alias)
+	}
+	return names
+}
+
+// ParentName mengembalikan nama skill induk, "" kalau tidak ada
+func (t *SkillTaxonomy) ParentName(s *Skill) string {
+	if s == nil || s.ParentID == nil {
+		return ""
+	}
+	if p, ok := t.skills[*s.ParentID]; ok {
+		return p.Name
+	}
+	return ""
+}
+
+// FindSkills mencari semua skill taxonomy (nama atau alias, sebagai kata utuh) yang
+// disebut di teks, mis. deskripsi job. Hasilnya nama kanonik, urut alfabet.
+func (t *SkillTaxonomy) FindSkills(text string) []string {
+	if t == nil {
+		return nil
+	}
+	lower := strings.ToLower(text)
+	found := map[string]bool{}
+	for id, matchers := range t.patterns {
+		for _, m := range matchers {
+			if m.match(text, lower) {
+				found[t.skills[id].Name] = true
+				break
+			}
+		}
+	}
+
+	names := make([]string, 0, len(found))
+	for n := range found {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mentionMatchers: pola kata utuh untuk skill dan semua alias-nya. Skill yang ada di
+// taxonomy memakai pola yang sudah di-compile; nama lain di-compile saat itu.
+func (t *SkillTaxonomy) mentionMatchers(name string) []skillMatcher {
+	if s, ok := t.Lookup(name); ok {
+		return t.patterns[s.ID]
+	}
+	return compileSkillMatchers([]string{name})
+}
+
+func compileSkillMatchers(names []string) []skillMatcher {
+	var matchers []skillMatcher
+	for _, n := range names {
+		if p := skillMentionPattern(n); p != nil {
+			matchers = append(matchers, skillMatcher{literal: NormalizeSkill(n), pattern: p})
+		}
+	}
+	return matchers
+}
+
+// SkillOverlap adalah perbandingan skill yang dibutuhkan job dengan skill kandidat
+type SkillOverlap struct {
+	Matched  []string          `json:"matched"`  // skill job yang dimiliki kandidat
+	Related  map[string]string `json:"related"`  // skill job → skill kandidat yang satu induk / turunannya
+	Missing  []string          `json:"missing"`  // skill job yang tidak dimiliki kandidat
+	Extra    []string          `json:"extra"`    // skill kandidat yang tidak disebut job
+	Coverage float64           `json:"coverage"` // (matched + 0.5 × related) / jumlah skill job
+}
+
+// ComputeSkillOverlap membandingkan skill job dengan skill kandidat setelah keduanya
+// dinormalisasi lewat taxonomy. Skill kandidat yang satu induk dengan skill job
+// (mis. job minta "Go", kandidat punya "Gin") dihitung setengah.
+func (t *SkillTaxonomy) ComputeSkillOverlap(jobSkills, candidateSkills []string) SkillOverlap {
+	o := SkillOverlap{Matched: []string{}, Related: map[string]string{}, Missing: []string{}, Extra: []string{}}
+
+	candidate := map[string]string{} // SkillKey(kanonik) → nama kanonik
+	for _, s := range candidateSkills {
+		name := t.Canonical(s)
+		if name != "" {
+			candidate[SkillKey(name)] = name
+		}
+	}
+
+	job := map[string]bool{}
+	for _, s := range jobSkills {
+		name := t.Canonical(s)
+		key := SkillKey(name)
+		if name == "" || job[key] {
+			continue
+		}
+		job[key] = true
+
+		if _, ok := candidate[key]; ok {
+			o.Matched = append(o.Matched, name)
+			continue
+		}
+		if rel := t.relatedSkill(name, candidate); rel != "" {
+			o.Related[name] = rel
+			continue
+		}
+		o.Missing = append(o.Missing, name)
+	}
+
+	for key, name := range candidate {
+		if !job[key] {
+			o.Extra = append(o.Extra, name)
+		}
+	}
+	sort.Strings(o.Matched)
+	sort.Strings(o.Missing)
+	sort.Strings(o.Extra)
+
+	if len(job) > 0 {
+		o.Coverage = (float64(len(o.Matched)) + 0.5*float64(len(o.Related))) / float64(len(job))
+	}
+	return o
+}
+
+// relatedSkill mencari skill kandidat yang induknya skill job, induk skill job, atau
+// punya induk yang sama
+func (t *SkillTaxonomy) relatedSkill(jobSkill string, candidate map[string]string) string {
+	js, ok := t.Lookup(jobSkill)
+	if !ok {
+		return ""
+	}
+	jobParent := t.ParentName(js)
+
+	names := make([]string, 0, len(candidate))
+	for _, n := range candidate {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cs, ok := t.Lookup(name)
+		if !ok {
+			continue
+		}
+		parent := t.ParentName(cs)
+		switch {
+		case parent == js.Name, jobParent != "" && cs.Name == jobParent, jobParent != "" && parent == jobParent:
+			return cs.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// StoredFile adalah file original (CV / project) yang disimpan di blob storage
+type StoredFile struct {
+	ID         uint   `gorm:"primaryKey"`
+	UploadID   uint   `gorm:"not null;index"`
+	Kind       string `gorm:"type:enum('cv','project');not null"`
+	Filename   string `gorm:"size:255;not null"`
+	MimeType   string `gorm:"size:127;not null"`
+	Size       int64  `gorm:"not null"`
+	SHA256     string `gorm:"column:sha256;size:64;not null;index"`
+	StorageKey string `gorm:"size:512;not null"`
+	CreatedAt  time.Time
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Tournament: ranking shortlist kandidat untuk satu job lewat perbandingan pairwise oleh LLM
+type Tournament struct {
+	ID        uint   `gorm:"primaryKey"`
+	JobID     uint   `gorm:"not null;index"`
+	Status    string `gorm:"type:enum('running','completed','failed');default:'running'"`
+	UploadIDs string `gorm:"type:json;not null"` // shortlist upload id (JSON array)
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// PairwiseComparison menyimpan satu keputusan "A vs B" dari model
+type PairwiseComparison struct {
+	ID           uint    `gorm:"primaryKey"`
+	TournamentID uint    `gorm:"not null;index"`
+	UploadAID    uint    `gorm:"not null"`
+	UploadBID    uint    `gorm:"not null"`
+	Status       string  `gorm:"type:enum('queued','processing','completed','failed');default:'queued'"`
+	Winner       string  `gorm:"size:8"` // "a", "b" atau "tie"
+	Confidence   float64 // 0-1
+	Reasoning    string  `gorm:"type:text"`
+	// Mapping redaksi kandidat A / B (placeholder [A_...] / [B_...]) untuk reasoning
+	RedactionMappingAID *uint
+	RedactionMappingBID *uint
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// TournamentStanding adalah posisi satu kandidat di hasil tournament
+type TournamentStanding struct {
+	Rank     int     `json:"rank"`
+	UploadID uint    `json:"upload_id"`
+	Strength float64 `json:"strength"` // Bradley-Terry strength, dinormalisasi (jumlah = 1)
+	Wins     float64 `json:"wins"`     // tie dihitung 0.5
+	Played   int     `json:"played"`
+}
+
+const (
+	btIterations = 200
+	btTolerance  = 1e-9
+	// Pseudo-count supaya kandidat yang belum pernah menang tetap punya strength > 0
+	btPrior = 0.1
+)
+
+// BradleyTerryRanking menghitung ranking dari perbandingan yang sudah completed
+// menggunakan algoritma MM (minorization-maximization) untuk model Bradley-Terry.
+func BradleyTerryRanking(uploadIDs []uint, comparisons []PairwiseComparison) []TournamentStanding {
+	n := len(uploadIDs)
+	index := make(map[uint]int, n)
+	for i, id := range uploadIDs {
+		index[id] = i
+	}
+
+	wins := make([]float64, n)
+	played := make([]int, n)
+	games := make([][]float64, n)
+	for i := range games {
+		games[i] = make([]float64, n)
+	}
+
+	for _, c := range comparisons {
+		if c.Status != "completed" {
+			continue
+		}
+		a, okA := index[c.UploadAID]
+		b, okB := index[c.UploadBID]
+		if !okA || !okB || a == b {
+			continue
+		}
+		games[a][b]++
+		games[b][a]++
+		played[a]++
+		played[b]++
+		switch c.Winner {
+		case "a":
+			wins[a]++
+		case "b":
+			wins[b]++
+		default:
+			wins[a] += 0.5
+			wins[b] += 0.5
+		}
+	}
+
+	strength := make([]float64, n)
+	for i := range strength {
+		strength[i] = 1
+	}
+
+	for iter := 0; iter < btIterations; iter++ {
+		next := make([]float64, n)
+		var total float64
+		for i := 0; i < n; i++ {
+			var denom float64
+			for j := 0; j < n; j++ {
+				if i == j {
+					continue
+				}
+				// Setiap pasangan dapat pseudo-game supaya matriks tetap terhubung
+				g := games[i][j] + 2*btPrior
+				denom += g / (strength[i] + strength[j])
+			}
+			if denom == 0 {
+				next[i] = strength[i]
+			} else {
+				next[i] = (wins[i] + btPrior*float64(n-1)) / denom
+			}
+			total += next[i]
+		}
+
+		var delta float64
+		for i := range next {
+			next[i] /= total
+			delta = math.Max(delta, math.Abs(next[i]-strength[i]))
+		}
+		strength = next
+		if delta < btTolerance {
+			break
+		}
+	}
+
+	standings := make([]TournamentStanding, n)
+	for i, id := range uploadIDs {
+		standings[i] = TournamentStanding{
+			UploadID: id,
+			Strength: strength[i],
+			Wins:     wins[i],
+			Played:   played[i],
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Strength != standings[j].Strength {
+			return standings[i].Strength > standings[j].Strength
+		}
+		return standings[i].UploadID < standings[j].UploadID
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+	}
+	return standings
+}
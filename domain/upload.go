@@ -3,10 +3,15 @@ package domain
 import "time"
 
 type Upload struct {
-	ID             uint   `gorm:"primaryKey"`
-	CandidateName  string `gorm:"size:255"`
-	CandidateEmail string `gorm:"size:255"`
-	CVText         string `gorm:"type:longtext;not null"`
-	ProjectText    string `gorm:"type:longtext;not null"`
-	CreatedAt      time.Time
+	ID                uint       `gorm:"primaryKey"`
+	CandidateID       *uint      `gorm:"index"`
+	Candidate         *Candidate `gorm:"constraint:OnDelete:SET NULL"`
+	BatchID           *uint      `gorm:"index"` // diisi kalau upload berasal dari bulk upload
+	Status            string     `gorm:"type:enum('extracting','ready','failed');not null;default:'ready';index"`
+	ExtractionError   string     `gorm:"type:text"` // alasan kalau status failed
+	CVText            string     `gorm:"type:longtext;not null"`
+	ProjectText       string     `gorm:"type:longtext;not null"`
+	ExtractionFlagged bool       `gorm:"not null;default:false"` // ada halaman kosong / karakter aneh → perlu dicek manual
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
 }
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// UploadBatch adalah satu bulk upload (ZIP / tar.gz berisi banyak pasangan CV + project).
+// Kalau JobID diisi, setiap upload langsung dievaluasi terhadap job itu setelah teksnya siap.
+type UploadBatch struct {
+	ID            uint   `gorm:"primaryKey"`
+	JobID         *uint  `gorm:"index"`
+	Filename      string `gorm:"size:255;not null"`
+	ItemCount     int    `gorm:"not null"`
+	AcceptedCount int    `gorm:"not null"`
+	CreatedAt     time.Time
+}
+
+// UploadBatchItem adalah satu pasangan CV + project di dalam batch, atau file yang tidak
+// bisa diproses (status rejected beserta alasannya)
+type UploadBatchItem struct {
+	ID              uint   `gorm:"primaryKey"`
+	BatchID         uint   `gorm:"not null;index"`
+	Name            string `gorm:"size:255;not null"` // nama pasangan, mis. folder kandidat
+	CVFilename      string `gorm:"size:512"`
+	ProjectFilename string `gorm:"size:512"`
+	CandidateName   string `gorm:"size:255"`
+	CandidateEmail  string `gorm:"size:255"`
+	UploadID        *uint  `gorm:"index"`
+	Status          string `gorm:"type:enum('accepted','rejected');not null"`
+	Error           string `gorm:"type:text"`
+	CreatedAt       time.Time
+}
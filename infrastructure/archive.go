@@ -0,0 +1,181 @@
+package infrastructure
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	ErrUnsupportedArchive = errors.New("unsupported archive format (expected zip or tar.gz)")
+	ErrArchiveTooLarge    = errors.New("archive exceeds limits")
+)
+
+// ArchiveLimits membatasi isi archive supaya zip bomb / archive raksasa tidak menghabiskan memory
+type ArchiveLimits struct {
+	MaxFiles     int   // jumlah file (bukan direktori)
+	MaxFileSize  int64 // ukuran satu file setelah di-decompress
+	MaxTotalSize int64 // total ukuran semua file setelah di-decompress
+}
+
+// ArchiveFile adalah satu file di dalam archive. Path sudah dinormalisasi (pakai "/",
+// tanpa "./" atau ".." di depan).
+type ArchiveFile struct {
+	Path string
+	Data []byte
+}
+
+// ReadArchive membaca semua file di ZIP atau tar.gz ke memory. Isi archive tidak pernah
+// ditulis ke disk; path dengan ".." dan symlink tetap dilewati supaya tidak ada file yang
+// "keluar" dari archive. Batas jumlah dan ukuran file dicek sambil decompress.
+func ReadArchive(data []byte, limits ArchiveLimits) ([]ArchiveFile, error) {
+	var files []ArchiveFile
+	err := WalkArchive(bytes.NewReader(data), int64(len(data)), limits, func(p string, r io.Reader) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", p, err)
+		}
+		files = append(files, ArchiveFile{Path: p, Data: data})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// WalkArchive membaca ZIP atau tar.gz langsung dari r (tanpa memuat seluruh archive ke
+// memory) dan memanggil fn untuk setiap file secara berurutan. fn boleh tidak membaca isi
+// file sampai habis: sisanya tetap di-decompress supaya batas jumlah dan ukuran berlaku sama
+// seperti ReadArchive. Path sudah dinormalisasi dengan aturan yang sama.
+func WalkArchive(r io.ReaderAt, size int64, limits ArchiveLimits, fn func(path string, r io.Reader) error) error {
+	magic := make([]byte, 4)
+	n, _ := r.ReadAt(magic, 0)
+	magic = magic[:n]
+
+	w := archiveWalker{limits: limits, fn: fn}
+	switch {
+	case bytes.HasPrefix(magic, []byte("PK\x03\x04")), bytes.HasPrefix(magic, []byte("PK\x05\x06")):
+		return w.walkZip(r, size)
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		return w.walkTarGz(io.NewSectionReader(r, 0, size))
+	default:
+		return ErrUnsupportedArchive
+	}
+}
+
+type archiveWalker struct {
+	limits ArchiveLimits
+	fn     func(path string, r io.Reader) error
+	count  int
+	total  int64
+}
+
+func (w *archiveWalker) walkZip(r io.ReaderAt, size int64) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedArchive, err)
+	}
+
+	for _, f := range zr.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", f.Name, err)
+		}
+		err = w.visit(f.Name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *archiveWalker) walkTarGz(r io.Reader) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupportedArchive, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrUnsupportedArchive, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := w.visit(hdr.Name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+func (w *archiveWalker) visit(name string, r io.Reader) error {
+	p, ok := cleanArchivePath(name)
+	if !ok {
+		return nil
+	}
+	if w.count >= w.limits.MaxFiles {
+		return fmt.Errorf("%w: more than %d files", ErrArchiveTooLarge, w.limits.MaxFiles)
+	}
+	w.count++
+
+	// Ukuran di header archive bisa bohong, jadi batasnya dicek sambil membaca
+	cr := &countingReader{r: io.LimitReader(r, w.limits.MaxFileSize+1)}
+	fnErr := w.fn(p, cr)
+	if _, err := io.Copy(io.Discard, cr); err != nil {
+		return fmt.Errorf("failed to read %s: %w", p, err)
+	}
+	if cr.n > w.limits.MaxFileSize {
+		return fmt.Errorf("%w: %s exceeds %d MB", ErrArchiveTooLarge, p, w.limits.MaxFileSize>>20)
+	}
+	w.total += cr.n
+	if w.total > w.limits.MaxTotalSize {
+		return fmt.Errorf("%w: uncompressed size exceeds %d MB", ErrArchiveTooLarge, w.limits.MaxTotalSize>>20)
+	}
+	return fnErr
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// cleanArchivePath menormalisasi path di archive. Entry dengan ".." dan sampah dari VCS /
+// macOS (.git, __MACOSX, ._file, .DS_Store) dilewati.
+func cleanArchivePath(name string) (string, bool) {
+	name = strings.TrimLeft(strings.ReplaceAll(name, "\\", "/"), "/")
+	for _, part := range strings.Split(name, "/") {
+		switch {
+		case part == "..", part == ".git", part == "__MACOSX", part == ".DS_Store", strings.HasPrefix(part, "._"):
+			return "", false
+		}
+	}
+
+	p := path.Clean(name)
+	if p == "." || p == "" {
+		return "", false
+	}
+	return p, true
+}
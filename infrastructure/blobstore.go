@@ -0,0 +1,51 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+// ErrBlobNotFound dikembalikan kalau key tidak ada di storage
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore menyimpan file original yang di-upload kandidat
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewBlobStore memilih implementasi berdasarkan BLOB_STORE (local | s3)
+func NewBlobStore() BlobStore {
+	switch strings.ToLower(os.Getenv("BLOB_STORE")) {
+	case "", "local":
+		root := os.Getenv("BLOB_LOCAL_DIR")
+		if root == "" {
+			root = "./storage" // default
+		}
+		store, err := NewLocalBlobStore(root)
+		if err != nil {
+			log.Fatalf("failed to init local blob store: %v", err)
+		}
+		return store
+	case "s3":
+		store, err := NewS3BlobStore(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		})
+		if err != nil {
+			log.Fatalf("failed to init S3 blob store: %v", err)
+		}
+		return store
+	default:
+		log.Fatalf("unknown BLOB_STORE: %s", os.Getenv("BLOB_STORE"))
+		return nil
+	}
+}
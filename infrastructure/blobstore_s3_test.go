@@ -0,0 +1,306 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	fakeS3AccessKey = "AKIDEXAMPLE"
+	fakeS3SecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	fakeS3Region    = "ap-southeast-1"
+	fakeS3Bucket    = "cv-files"
+)
+
+type fakeS3Object struct {
+	data        []byte
+	contentType string
+}
+
+// fakeS3 adalah stand-in S3 minimal (path-style PUT / GET / DELETE) yang memverifikasi
+// signature SigV4 setiap request secara independen dari S3BlobStore.sign
+type fakeS3 struct {
+	t         *testing.T
+	secretKey string
+
+	mu      sync.Mutex
+	objects map[string]fakeS3Object
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	f := &fakeS3{t: t, secretKey: fakeS3SecretKey, objects: map[string]fakeS3Object{}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f.verifySignature(r); err != nil {
+		f.t.Logf("rejected %s %s: %v", r.Method, r.URL.Path, err)
+		http.Error(w, "<Error><Code>SignatureDoesNotMatch</Code></Error>", http.StatusForbidden)
+		return
+	}
+
+	prefix := "/" + fakeS3Bucket + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.Error(w, "<Error><Code>NoSuchBucket</Code></Error>", http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, prefix)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.ContentLength != int64(len(data)) {
+			http.Error(w, "<Error><Code>IncompleteBody</Code></Error>", http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = fakeS3Object{data: data, contentType: r.Header.Get("Content-Type")}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		obj, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Write(obj.data)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// verifySignature menghitung ulang signature AWS SigV4 dari request yang diterima server
+func (f *fakeS3) verifySignature(r *http.Request) error {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 ") {
+		return errors.New("missing AWS4-HMAC-SHA256 authorization")
+	}
+	fields := map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(auth, "AWS4-HMAC-SHA256 "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) == 2 {
+			fields[kv[0]] = kv[1]
+		}
+	}
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	signedAt, err := time.Parse("20060102T150405Z", amzDate)
+	if err != nil {
+		return errors.New("invalid X-Amz-Date")
+	}
+	if d := time.Since(signedAt); d > 15*time.Minute || d < -15*time.Minute {
+		return errors.New("X-Amz-Date outside the allowed clock skew")
+	}
+	if r.Header.Get("X-Amz-Content-Sha256") != "UNSIGNED-PAYLOAD" {
+		return errors.New("unexpected X-Amz-Content-Sha256")
+	}
+
+	scope := amzDate[:8] + "/" + fakeS3Region + "/s3/aws4_request"
+	if fields["Credential"] != fakeS3AccessKey+"/"+scope {
+		return errors.New("unexpected credential scope " + fields["Credential"])
+	}
+
+	signed := strings.Split(fields["SignedHeaders"], ";")
+	if !sort.StringsAreSorted(signed) {
+		return errors.New("signed headers are not sorted")
+	}
+	required := map[string]bool{"host": false, "x-amz-date": false, "x-amz-content-sha256": false}
+	var canonicalHeaders strings.Builder
+	for _, name := range signed {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		if _, ok := required[name]; ok {
+			required[name] = true
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	for name, ok := range required {
+		if !ok {
+			return errors.New("header not signed: " + name)
+		}
+	}
+
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		r.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		fields["SignedHeaders"],
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashed[:])
+
+	key := hmacSHA256([]byte("AWS4"+f.secretKey), amzDate[:8])
+	key = hmacSHA256(key, fakeS3Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	if want := hex.EncodeToString(hmacSHA256(key, stringToSign)); fields["Signature"] != want {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func newTestS3Store(t *testing.T, endpoint, secretKey string) *S3BlobStore {
+	store, err := NewS3BlobStore(S3Config{
+		Endpoint:  endpoint,
+		Region:    fakeS3Region,
+		Bucket:    fakeS3Bucket,
+		AccessKey: fakeS3AccessKey,
+		SecretKey: secretKey,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestS3BlobStorePutGetDelete(t *testing.T) {
+	fake, srv := newFakeS3(t)
+	store := newTestS3Store(t, srv.URL, fakeS3SecretKey)
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		key         string
+		data        []byte
+		contentType string
+	}{
+		{"pdf", "originals/2024/01/02/abc/cv.pdf", []byte("%PDF-1.4 fake cv"), "application/pdf"},
+		{"key needing escaping", "originals/2024/01/02/a b+c/project (final).docx", []byte("PK\x03\x04 project"), "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+		{"empty file", "originals/empty.txt", []byte{}, "text/plain"},
+		{"binary data", "originals/blob.bin", bytes.Repeat([]byte{0, 1, 2, 0xff}, 64*1024), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := store.Put(ctx, tt.key, bytes.NewReader(tt.data), int64(len(tt.data)), tt.contentType); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+
+			fake.mu.Lock()
+			stored, ok := fake.objects[tt.key]
+			fake.mu.Unlock()
+			if !ok {
+				t.Fatalf("object %q not stored", tt.key)
+			}
+			if stored.contentType != tt.contentType {
+				t.Errorf("content type = %q, want %q", stored.contentType, tt.contentType)
+			}
+
+			rc, err := store.Get(ctx, tt.key)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			got, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.data) {
+				t.Errorf("size = %d, want %d", len(got), len(tt.data))
+			}
+			if sha256.Sum256(got) != sha256.Sum256(tt.data) {
+				t.Error("checksum of downloaded blob does not match the uploaded data")
+			}
+
+			if err := store.Delete(ctx, tt.key); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if _, err := store.Get(ctx, tt.key); !errors.Is(err, ErrBlobNotFound) {
+				t.Errorf("Get after Delete: err = %v, want ErrBlobNotFound", err)
+			}
+		})
+	}
+}
+
+func TestS3BlobStoreDeleteMissingKey(t *testing.T) {
+	_, srv := newFakeS3(t)
+	store := newTestS3Store(t, srv.URL, fakeS3SecretKey)
+	if err := store.Delete(context.Background(), "originals/missing.pdf"); err != nil {
+		t.Errorf("Delete of a missing key: %v", err)
+	}
+}
+
+func TestS3BlobStoreSizeMismatch(t *testing.T) {
+	_, srv := newFakeS3(t)
+	store := newTestS3Store(t, srv.URL, fakeS3SecretKey)
+	// Size yang tidak sesuai isi reader tidak boleh tersimpan diam-diam
+	err := store.Put(context.Background(), "originals/short.pdf", strings.NewReader("abc"), 10, "application/pdf")
+	if err == nil {
+		t.Error("Put with a size larger than the data should fail")
+	}
+}
+
+func TestS3BlobStoreRejectsWrongSecret(t *testing.T) {
+	_, srv := newFakeS3(t)
+	store := newTestS3Store(t, srv.URL, "wrong-secret")
+	ctx := context.Background()
+
+	err := store.Put(ctx, "originals/cv.pdf", strings.NewReader("data"), 4, "application/pdf")
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Put with a wrong secret: err = %v, want status 403", err)
+	}
+	if _, err := store.Get(ctx, "originals/cv.pdf"); err == nil || errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Get with a wrong secret: err = %v, want signature error", err)
+	}
+}
+
+func TestS3BlobStoreSignHeaders(t *testing.T) {
+	store := newTestS3Store(t, "http://localhost:9000", fakeS3SecretKey)
+	req, err := store.newRequest(context.Background(), http.MethodPut, "originals/cv.pdf", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/pdf")
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	store.sign(req, now)
+
+	if got := req.Header.Get("X-Amz-Date"); got != "20240102T030405Z" {
+		t.Errorf("X-Amz-Date = %q", got)
+	}
+	if got := req.Header.Get("X-Amz-Content-Sha256"); got != "UNSIGNED-PAYLOAD" {
+		t.Errorf("X-Amz-Content-Sha256 = %q", got)
+	}
+	auth := req.Header.Get("Authorization")
+	for _, want := range []string{
+		"AWS4-HMAC-SHA256 ",
+		"Credential=" + fakeS3AccessKey + "/20240102/" + fakeS3Region + "/s3/aws4_request",
+		"SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date",
+		"Signature=",
+	} {
+		if !strings.Contains(auth, want) {
+			t.Errorf("Authorization %q does not contain %q", auth, want)
+		}
+	}
+
+	// Signature deterministik untuk waktu, request dan kredensial yang sama
+	again, _ := store.newRequest(context.Background(), http.MethodPut, "originals/cv.pdf", nil)
+	again.Header.Set("Content-Type", "application/pdf")
+	store.sign(again, now)
+	if again.Header.Get("Authorization") != auth {
+		t.Error("signature is not deterministic")
+	}
+}
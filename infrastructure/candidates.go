@@ -0,0 +1,117 @@
+package infrastructure
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"cv-evaluator/domain"
+)
+
+// FindOrCreateCandidate mencari kandidat berdasarkan email (normalized), atau membuat kandidat baru.
+// Kalau email kosong, selalu dibuat kandidat baru karena tidak ada kunci untuk deduplikasi.
+func FindOrCreateCandidate(db *gorm.DB, name string, email string) (*domain.Candidate, error) {
+	name = strings.TrimSpace(name)
+	email = domain.NormalizeEmail(email)
+
+	if email == "" {
+		candidate := &domain.Candidate{Name: name}
+		if err := db.Create(candidate).Error; err != nil {
+			return nil, fmt.Errorf("failed to create candidate: %w", err)
+		}
+		return candidate, nil
+	}
+
+	var candidate domain.Candidate
+	err := db.
+		Where(domain.Candidate{Email: &email}).
+		Attrs(domain.Candidate{Name: name}).
+		FirstOrCreate(&candidate).Error
+	if err != nil {
+		// Request lain bisa membuat kandidat dengan email yang sama di antara SELECT dan INSERT
+		// FirstOrCreate; INSERT-nya lalu gagal karena unique index email, jadi cari ulang
+		candidate = domain.Candidate{}
+		if retryErr := db.Where("email = ?", email).First(&candidate).Error; retryErr != nil {
+			return nil, fmt.Errorf("failed to find or create candidate: %w", err)
+		}
+	}
+
+	// Nama terbaru yang diisi kandidat dianggap paling valid
+	if name != "" && candidate.Name != name {
+		candidate.Name = name
+		if err := db.Model(&candidate).Update("name", name).Error; err != nil {
+			return nil, fmt.Errorf("failed to update candidate name: %w", err)
+		}
+	}
+
+	return &candidate, nil
+}
+
+// backfillUploadCandidates memindahkan candidate_name/candidate_email lama di tabel uploads ke
+// tabel candidates, lalu menghapus kedua kolom itu. Backfill jadi hanya jalan sekali: upload
+// yang candidate_id-nya kemudian NULL (kandidat dihapus) tidak dibuatkan kandidat lagi dari
+// data lama.
+func backfillUploadCandidates(db *gorm.DB) {
+	migrator := db.Migrator()
+	if !migrator.HasColumn("uploads", "candidate_email") {
+		return
+	}
+
+	var rows []struct {
+		ID             uint
+		CandidateName  string
+		CandidateEmail string
+	}
+	if err := db.Table("uploads").
+		Select("id", "candidate_name", "candidate_email").
+		Where("candidate_id IS NULL").
+		Order("id").
+		Scan(&rows).Error; err != nil {
+		log.Fatalf("failed to load uploads for candidate backfill: %v", err)
+	}
+
+	for _, row := range rows {
+		candidate, err := FindOrCreateCandidate(db, row.CandidateName, row.CandidateEmail)
+		if err != nil {
+			log.Fatalf("failed to backfill candidate for upload %d: %v", row.ID, err)
+		}
+		if err := db.Table("uploads").Where("id = ?", row.ID).Update("candidate_id", candidate.ID).Error; err != nil {
+			log.Fatalf("failed to link upload %d to candidate: %v", row.ID, err)
+		}
+	}
+	if len(rows) > 0 {
+		fmt.Printf("✅ Linked %d existing uploads to candidates\n", len(rows))
+	}
+
+	// Kolom dihapus setelah semua upload ter-link; kalau proses berhenti di tengah, upload
+	// yang belum ter-link diproses lagi saat startup berikutnya
+	for _, column := range []string{"candidate_name", "candidate_email"} {
+		if !migrator.HasColumn("uploads", column) {
+			continue
+		}
+		if err := migrator.DropColumn("uploads", column); err != nil {
+			log.Fatalf("failed to drop uploads.%s after candidate backfill: %v", column, err)
+		}
+	}
+}
+
+// LoadCandidateDocuments menyiapkan input prompt untuk satu upload: teks CV, teks project,
+// link (GitHub, LinkedIn, portfolio) yang ditemukan di kedua dokumen dan timeline pengalaman
+func LoadCandidateDocuments(db *gorm.DB, upload domain.Upload) (CandidateDocuments, error) {
+	var links []domain.DocumentLink
+	if err := db.Where("upload_id = ?", upload.ID).Order("kind, id").Find(&links).Error; err != nil {
+		return CandidateDocuments{}, fmt.Errorf("failed to load links for upload %d: %w", upload.ID, err)
+	}
+	experience, err := loadExperienceFacts(db, upload.ID)
+	if err != nil {
+		return CandidateDocuments{}, fmt.Errorf("failed to load experience timeline for upload %d: %w", upload.ID, err)
+	}
+	return CandidateDocuments{
+		CVText:      upload.CVText,
+		ProjectText: upload.ProjectText,
+		Links:       links,
+		Experience:  experience,
+	}, nil
+}
@@ -0,0 +1,340 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
+	"cv-evaluator/domain"
+)
+
+// ParsedCV adalah hasil parsing terstruktur CV oleh model, sesuai JSON di cvParsePrompt
+type ParsedCV struct {
+	Contact struct {
+		Name     string `json:"name"`
+		Email    string `json:"email"`
+		Phone    string `json:"phone"`
+		Location string `json:"location"`
+		Headline string `json:"headline"`
+	} `json:"contact"`
+	Summary   string `json:"summary"`
+	Positions []struct {
+		Title       string `json:"title"`
+		Company     string `json:"company"`
+		Location    string `json:"location"`
+		StartDate   string `json:"start_date"`
+		EndDate     string `json:"end_date"`
+		Current     bool   `json:"current"`
+		Description string `json:"description"`
+	} `json:"positions"`
+	Education []struct {
+		Institution string `json:"institution"`
+		Degree      string `json:"degree"`
+		Field       string `json:"field"`
+		StartDate   string `json:"start_date"`
+		EndDate     string `json:"end_date"`
+		Grade       string `json:"grade"`
+	} `json:"education"`
+	Skills []struct {
+		Name     string `json:"name"`
+		Category string `json:"category"`
+	} `json:"skills"`
+	Certifications []struct {
+		Name       string `json:"name"`
+		Issuer     string `json:"issuer"`
+		IssuedDate string `json:"issued_date"`
+		ExpiryDate string `json:"expiry_date"`
+	} `json:"certifications"`
+	Languages []struct {
+		Language    string `json:"language"`
+		Proficiency string `json:"proficiency"`
+	} `json:"languages"`
+}
+
+const cvParsePrompt = `Extract structured data from the following CV. Only use information that is written in the CV, never guess.
+
+CV Input:
+%s
+
+%s
+
+Return strict JSON with structure:
+{
+  "contact": {"name": string, "email": string, "phone": string, "location": string, "headline": string},
+  "summary": string,
+  "positions": [{"title": string, "company": string, "location": string, "start_date": string, "end_date": string, "current": boolean, "description": string}],
+  "education": [{"institution": string, "degree": string, "field": string, "start_date": string, "end_date": string, "grade": string}],
+  "skills": [{"name": string, "category": "technical" | "tool" | "soft" | "other"}],
+  "certifications": [{"name": string, "issuer": string, "issued_date": string, "expiry_date": string}],
+  "languages": [{"language": string, "proficiency": string}]
+}
+
+Rules:
+- Dates use "YYYY-MM" (or "YYYY" if the month is unknown); use "" when the date is not written.
+- For a position the candidate still holds, set "current": true and "end_date": "".
+- List positions and education in the order they appear in the CV.
+- Every skill is a separate item ("Go, Python" becomes two skills); "technical" is a language / framework / concept, "tool" is a product or platform (Docker, AWS, Jira).
+- Use "" or [] for missing fields.
+- Personal details may be replaced with placeholders such as [NAME_1], [EMAIL_1] or [ADDRESS_1]. Copy a placeholder exactly as written
+  into the field it belongs to (e.g. "name": "[NAME_1]"); never guess the hidden value.
+
+IMPORTANT: Return ONLY the raw JSON without any markdown formatting, code blocks, or additional text.`
+
+// ParseCV meminta model mengubah teks CV jadi record terstruktur
+func (g *GeminiClient) ParseCV(ctx context.Context, cvText string) (*ParsedCV, error) {
+	nonce := newPromptNonce()
+	prompt := fmt.Sprintf(cvParsePrompt, untrustedBlock("CV", nonce, g.fitCVForParsing(cvText)), untrustedContentRules(nonce))
+	result, err := g.generateJSONWithFallback(ctx, prompt, "cv parsing")
+	if err != nil {
+		return nil, err
+	}
+
+	// Response sudah berupa map; di-encode ulang supaya bisa di-decode ke struct bertipe
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode parsed CV: %w", err)
+	}
+	var parsed ParsedCV
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, fmt.Errorf("parsed CV has unexpected structure: %w", err)
+	}
+	return &parsed, nil
+}
+
+var (
+	cvMonthPattern = regexp.MustCompile(`^(\d{4})[-/.](\d{1,2})(?:[-/.]\d{1,2})?$`)
+	cvYearPattern  = regexp.MustCompile(`^(\d{4})$`)
+)
+
+// parseCVDate menerima "YYYY-MM", "YYYY-MM-DD" atau "YYYY" (jadi Januari). Selain itu
+// (kosong, "present", teks bebas) → nil.
+func parseCVDate(s string) *time.Time {
+	s = strings.TrimSpace(s)
+	var year, month int
+	if m := cvMonthPattern.FindStringSubmatch(s); m != nil {
+		year, _ = strconv.Atoi(m[1])
+		month, _ = strconv.Atoi(m[2])
+	} else if m := cvYearPattern.FindStringSubmatch(s); m != nil {
+		year, _ = strconv.Atoi(m[1])
+		month = 1
+	} else {
+		return nil
+	}
+	if year < 1900 || year > 2100 || month < 1 || month > 12 {
+		return nil
+	}
+	t := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+// isPresent: end date seperti "present", "now", "sekarang"
+func isPresent(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "present", "current", "now", "ongoing", "sekarang", "saat ini":
+		return true
+	}
+	return false
+}
+
+// clip memotong string (per rune) supaya muat di kolom varchar
+func clip(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// unredact mengembalikan placeholder redaksi di semua field teks hasil parsing ke nilai aslinya
+func (p *ParsedCV) unredact(entries []domain.RedactionEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	u := func(fields ...*string) {
+		for _, f := range fields {
+			*f = domain.Unredact(*f, entries)
+		}
+	}
+	u(&p.Contact.Name, &p.Contact.Email, &p.Contact.Phone, &p.Contact.Location, &p.Contact.Headline, &p.Summary)
+	for i := range p.Positions {
+		pos := &p.Positions[i]
+		u(&pos.Title, &pos.Company, &pos.Location, &pos.Description)
+	}
+	for i := range p.Education {
+		e := &p.Education[i]
+		u(&e.Institution, &e.Degree, &e.Field, &e.Grade)
+	}
+	for i := range p.Certifications {
+		u(&p.Certifications[i].Name, &p.Certifications[i].Issuer)
+	}
+}
+
+// SaveCVProfile menyimpan hasil parsing CV sebuah upload. Record lama (kalau parsing
+// diulang) diganti dalam satu transaksi. entries adalah mapping redaksi teks CV yang
+// dikirim ke model (nil kalau tidak diredaksi); placeholder di hasil parsing dikembalikan
+// ke nilai asli sebelum disimpan.
+func SaveCVProfile(db *gorm.DB, uploadID uint, parsed *ParsedCV, entries []domain.RedactionEntry) (domain.CVProfile, error) {
+	parsed.unredact(entries)
+	profile := domain.CVProfile{
+		UploadID: uploadID,
+		Status:   "parsed",
+		FullName: clip(parsed.Contact.Name, 255),
+		Email:    clip(domain.NormalizeEmail(parsed.Contact.Email), 255),
+		Phone:    clip(parsed.Contact.Phone, 64),
+		Location: clip(parsed.Contact.Location, 255),
+		Headline: clip(parsed.Contact.Headline, 255),
+		Summary:  strings.TrimSpace(parsed.Summary),
+	}
+
+	var positions []domain.CVPosition
+	for i, p := range parsed.Positions {
+		if strings.TrimSpace(p.Title) == "" && strings.TrimSpace(p.Company) == "" {
+			continue
+		}
+		pos := domain.CVPosition{
+			UploadID:    uploadID,
+			Title:       clip(p.Title, 255),
+			Company:     clip(p.Company, 255),
+			Location:    clip(p.Location, 255),
+			StartDate:   parseCVDate(p.StartDate),
+			EndDate:     parseCVDate(p.EndDate),
+			Current:     p.Current || isPresent(p.EndDate),
+			Description: strings.TrimSpace(p.Description),
+			SortOrder:   i,
+		}
+		if pos.Current {
+			pos.EndDate = nil
+		}
+		positions = append(positions, pos)
+	}
+
+	var education []domain.CVEducation
+	for i, e := range parsed.Education {
+		if strings.TrimSpace(e.Institution) == "" && strings.TrimSpace(e.Degree) == "" {
+			continue
+		}
+		education = append(education, domain.CVEducation{
+			UploadID:    uploadID,
+			Institution: clip(e.Institution, 255),
+			Degree:      clip(e.Degree, 128),
+			Field:       clip(e.Field, 255),
+			StartDate:   parseCVDate(e.StartDate),
+			EndDate:     parseCVDate(e.EndDate),
+			Grade:       clip(e.Grade, 64),
+			SortOrder:   i,
+		})
+	}
+
+	taxonomy, err := LoadSkillTaxonomy(db)
+	if err != nil {
+		return profile, err
+	}
+
+	var skills []domain.CVSkill
+	seen := map[string]bool{}
+	for _, s := range parsed.Skills {
+		name := clip(s.Name, 128)
+		skillID, key := canonicalSkill(taxonomy, name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		category := strings.ToLower(strings.TrimSpace(s.Category))
+		if !domain.SkillCategories[category] {
+			category = "other"
+		}
+		skills = append(skills, domain.CVSkill{UploadID: uploadID, SkillID: skillID, Name: name, NormalizedName: key, Category: category})
+	}
+
+	var certifications []domain.CVCertification
+	for _, c := range parsed.Certifications {
+		if strings.TrimSpace(c.Name) == "" {
+			continue
+		}
+		certifications = append(certifications, domain.CVCertification{
+			UploadID:  uploadID,
+			Name:      clip(c.Name, 255),
+			Issuer:    clip(c.Issuer, 255),
+			IssuedAt:  parseCVDate(c.IssuedDate),
+			ExpiresAt: parseCVDate(c.ExpiryDate),
+		})
+	}
+
+	var languages []domain.CVLanguage
+	for _, l := range parsed.Languages {
+		if strings.TrimSpace(l.Language) == "" {
+			continue
+		}
+		languages = append(languages, domain.CVLanguage{
+			UploadID:    uploadID,
+			Language:    clip(l.Language, 64),
+			Proficiency: clip(l.Proficiency, 64),
+		})
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteCVProfile(tx, uploadID); err != nil {
+			return err
+		}
+		if err := tx.Create(&profile).Error; err != nil {
+			return err
+		}
+		if len(positions) > 0 {
+			if err := tx.Create(&positions).Error; err != nil {
+				return err
+			}
+		}
+		if len(education) > 0 {
+			if err := tx.Create(&education).Error; err != nil {
+				return err
+			}
+		}
+		if len(skills) > 0 {
+			if err := tx.Create(&skills).Error; err != nil {
+				return err
+			}
+		}
+		if len(certifications) > 0 {
+			if err := tx.Create(&certifications).Error; err != nil {
+				return err
+			}
+		}
+		if len(languages) > 0 {
+			if err := tx.Create(&languages).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return profile, err
+}
+
+// SaveCVProfileFailure mencatat parsing yang gagal; record hasil parsing sebelumnya dihapus
+// supaya filter kandidat tidak memakai data yang sudah tidak sesuai
+func SaveCVProfileFailure(db *gorm.DB, uploadID uint, cause error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteCVProfile(tx, uploadID); err != nil {
+			return err
+		}
+		return tx.Create(&domain.CVProfile{UploadID: uploadID, Status: "failed", Error: cause.Error()}).Error
+	})
+}
+
+func deleteCVProfile(tx *gorm.DB, uploadID uint) error {
+	for _, model := range []interface{}{
+		&domain.CVProfile{}, &domain.CVPosition{}, &domain.CVEducation{},
+		&domain.CVSkill{}, &domain.CVCertification{}, &domain.CVLanguage{},
+	} {
+		if err := tx.Where("upload_id = ?", uploadID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return deleteExperienceTimeline(tx, uploadID)
+}
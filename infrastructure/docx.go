@@ -0,0 +1,395 @@
+package infrastructure
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Batas ukuran XML yang di-decompress supaya aman dari zip bomb
+const maxDOCXPartSize = 50 << 20
+
+// extractTextFromDOCX extracts text from a DOCX document, preserving paragraphs,
+// list items, tables and headers/footers
+func extractTextFromDOCX(data []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("failed to open DOCX: %w", err)
+	}
+
+	parts := map[string]*zip.File{}
+	for _, f := range zr.File {
+		parts[f.Name] = f
+	}
+
+	doc, ok := parts["word/document.xml"]
+	if !ok {
+		return "", fmt.Errorf("invalid DOCX: word/document.xml not found")
+	}
+
+	// Numbering bisa berasal dari style paragraf (mis. "ListBullet") di styles.xml
+	listStyles := map[string]int{}
+	if f, ok := parts["word/styles.xml"]; ok {
+		if listStyles, err = readDOCXListStyles(f); err != nil {
+			return "", err
+		}
+	}
+
+	body, err := readDOCXPart(doc, listStyles)
+	if err != nil {
+		return "", err
+	}
+
+	headers, err := readDOCXSections(parts, "word/header", listStyles)
+	if err != nil {
+		return "", err
+	}
+	footers, err := readDOCXSections(parts, "word/footer", listStyles)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	if len(headers) > 0 {
+		out.WriteString(strings.Join(headers, "\n"))
+		out.WriteString("\n\n")
+	}
+	out.WriteString(body)
+	if len(footers) > 0 {
+		out.WriteString("\n\n")
+		out.WriteString(strings.Join(footers, "\n"))
+	}
+
+	result := strings.TrimSpace(out.String())
+	if result == "" {
+		return "", fmt.Errorf("no text found in DOCX")
+	}
+
+	fmt.Printf("Successfully extracted text from DOCX (%d characters)\n", len(result))
+	return result, nil
+}
+
+// readDOCXSections membaca semua header*.xml / footer*.xml, duplikat di-skip
+// (header yang sama sering dipakai di first page / even / odd)
+func readDOCXSections(parts map[string]*zip.File, prefix string, listStyles map[string]int) ([]string, error) {
+	var names []string
+	for name := range parts {
+		if strings.HasPrefix(name, prefix) && path.Ext(name) == ".xml" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	seen := map[string]bool{}
+	var texts []string
+	for _, name := range names {
+		text, err := readDOCXPart(parts[name], listStyles)
+		if err != nil {
+			return nil, err
+		}
+		if text == "" || seen[text] {
+			continue
+		}
+		seen[text] = true
+		texts = append(texts, text)
+	}
+	return texts, nil
+}
+
+func readDOCXPart(f *zip.File, listStyles map[string]int) (string, error) {
+	rc, err := openDOCXPart(f)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	text, err := parseWordprocessingML(rc, listStyles)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", f.Name, err)
+	}
+	return text, nil
+}
+
+func readDOCXListStyles(f *zip.File) (map[string]int, error) {
+	rc, err := openDOCXPart(f)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	styles, err := parseDOCXListStyles(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", f.Name, err)
+	}
+	return styles, nil
+}
+
+func openDOCXPart(f *zip.File) (io.ReadCloser, error) {
+	if f.UncompressedSize64 > maxDOCXPartSize {
+		return nil, fmt.Errorf("DOCX part %s is too large", f.Name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(rc, maxDOCXPartSize), rc}, nil
+}
+
+// docxStyle adalah definisi numbering satu style paragraf di styles.xml
+type docxStyle struct {
+	basedOn  string
+	hasNum   bool // style punya numPr sendiri
+	numbered bool // numId bukan 0
+	level    int
+}
+
+// parseDOCXListStyles mengembalikan style paragraf yang memberi numbering (styleId → level
+// list), termasuk style yang mewarisi numbering lewat basedOn
+func parseDOCXListStyles(r io.Reader) (map[string]int, error) {
+	dec := xml.NewDecoder(r)
+	styles := map[string]*docxStyle{}
+	var cur *docxStyle
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "style":
+				cur = nil
+				if xmlAttr(t, "type") == "paragraph" {
+					cur = &docxStyle{}
+					styles[xmlAttr(t, "styleId")] = cur
+				}
+			case "basedOn":
+				if cur != nil {
+					cur.basedOn = xmlAttr(t, "val")
+				}
+			case "numPr":
+				if cur != nil {
+					cur.hasNum = true
+				}
+			case "numId":
+				if cur != nil {
+					cur.numbered = xmlAttr(t, "val") != "0"
+				}
+			case "ilvl":
+				if cur != nil {
+					fmt.Sscanf(xmlAttr(t, "val"), "%d", &cur.level)
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "style" {
+				cur = nil
+			}
+		}
+	}
+
+	list := map[string]int{}
+	for id := range styles {
+		// Cari style terdekat di rantai basedOn yang mendefinisikan numPr (dibatasi supaya
+		// rantai yang melingkar tidak membuat loop tanpa akhir)
+		s := styles[id]
+		for depth := 0; s != nil && !s.hasNum && depth < 20; depth++ {
+			s = styles[s.basedOn]
+		}
+		if s != nil && s.hasNum && s.numbered {
+			list[id] = s.level
+		}
+	}
+	return list, nil
+}
+
+// docxCell menampung teks satu sel tabel (paragraf di dalam sel digabung dengan spasi)
+type docxCell struct {
+	paragraphs []string
+}
+
+// docxTable menampung baris yang sedang dibangun untuk satu tabel (bisa nested)
+type docxTable struct {
+	row   []string
+	cells []*docxCell
+}
+
+// parseWordprocessingML mengubah XML WordprocessingML jadi plain text:
+// satu baris per paragraf, item list diberi "- " dengan indentasi per level,
+// dan baris tabel ditulis sebagai "a | b | c". listStyles adalah style paragraf
+// yang memberi numbering (hasil parseDOCXListStyles), boleh nil.
+func parseWordprocessingML(r io.Reader, listStyles map[string]int) (string, error) {
+	dec := xml.NewDecoder(r)
+
+	var (
+		out       []string
+		para      strings.Builder
+		inPara    bool
+		inText    bool
+		runDepth  int
+		isList    bool
+		listLevel int
+		tables    []*docxTable
+	)
+
+	emit := func(line string) {
+		if len(tables) > 0 {
+			t := tables[len(tables)-1]
+			if len(t.cells) > 0 {
+				cell := t.cells[len(t.cells)-1]
+				if line != "" {
+					cell.paragraphs = append(cell.paragraphs, line)
+				}
+				return
+			}
+		}
+		out = append(out, line)
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p":
+				inPara = true
+				isList = false
+				listLevel = 0
+				para.Reset()
+			case "pStyle":
+				if level, ok := listStyles[xmlAttr(t, "val")]; ok && inPara {
+					isList = true
+					listLevel = level
+				}
+			case "numPr":
+				isList = true
+			case "numId":
+				// numId 0 mematikan numbering dari style paragraf
+				if xmlAttr(t, "val") == "0" {
+					isList = false
+				}
+			case "ilvl":
+				if v := xmlAttr(t, "val"); v != "" {
+					fmt.Sscanf(v, "%d", &listLevel)
+				}
+			case "r":
+				runDepth++
+			case "t":
+				inText = true
+			case "tab":
+				// w:tab di luar run adalah definisi tab stop (w:pPr/w:tabs), bukan karakter
+				if inPara && runDepth > 0 {
+					para.WriteString("\t")
+				}
+			case "br", "cr":
+				if inPara && runDepth > 0 {
+					para.WriteString("\n")
+				}
+			case "tbl":
+				tables = append(tables, &docxTable{})
+			case "tc":
+				if len(tables) > 0 {
+					tbl := tables[len(tables)-1]
+					tbl.cells = append(tbl.cells, &docxCell{})
+				}
+			}
+
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "r":
+				if runDepth > 0 {
+					runDepth--
+				}
+			case "p":
+				if !inPara {
+					continue
+				}
+				inPara = false
+				line := strings.TrimRight(para.String(), " \t")
+				if isList && strings.TrimSpace(line) != "" {
+					line = strings.Repeat("  ", listLevel) + "- " + strings.TrimSpace(line)
+				}
+				emit(line)
+			case "tc":
+				if len(tables) > 0 {
+					tbl := tables[len(tables)-1]
+					if n := len(tbl.cells); n > 0 {
+						cell := tbl.cells[n-1]
+						tbl.cells = tbl.cells[:n-1]
+						tbl.row = append(tbl.row, strings.Join(cell.paragraphs, " "))
+					}
+				}
+			case "tr":
+				if len(tables) > 0 {
+					tbl := tables[len(tables)-1]
+					if strings.TrimSpace(strings.Join(tbl.row, "")) != "" {
+						line := strings.Join(tbl.row, " | ")
+						tables = tables[:len(tables)-1]
+						emit(line)
+						tables = append(tables, tbl)
+					}
+					tbl.row = nil
+				}
+			case "tbl":
+				if len(tables) > 0 {
+					tables = tables[:len(tables)-1]
+					emit("")
+				}
+			}
+
+		case xml.CharData:
+			if inText && inPara {
+				para.Write(t)
+			}
+		}
+	}
+
+	return collapseBlankLines(out), nil
+}
+
+func xmlAttr(el xml.StartElement, local string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// collapseBlankLines menggabungkan baris kosong berturut-turut jadi satu
+func collapseBlankLines(lines []string) string {
+	var b strings.Builder
+	blank := false
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			if !blank && b.Len() > 0 {
+				b.WriteString("\n")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return strings.TrimSpace(b.String())
+}
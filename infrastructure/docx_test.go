@@ -0,0 +1,129 @@
+package infrastructure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseWordprocessingML(t *testing.T) {
+	listStyles := map[string]int{"ListBullet": 0, "ListNumber": 1, "ListNumber2": 1}
+
+	tests := []struct {
+		name    string
+		fixture string
+		want    string
+	}{
+		{
+			name:    "paragraphs",
+			fixture: "paragraphs.xml",
+			want: "Jane Doe\n" +
+				"Backend Engineer\t2019 - 2023\n" +
+				"Jakarta, Indonesia\nRemote\n" +
+				"\n" +
+				"Summary",
+		},
+		{
+			name:    "numbered, bulleted and styled lists",
+			fixture: "lists.xml",
+			want: "Skills\n" +
+				"- Go\n" +
+				"  - Gin, GORM\n" +
+				"- MySQL\n" +
+				"- RabbitMQ\n" +
+				"  - Docker\n" +
+				"Not a list item\n" +
+				"Experience",
+		},
+		{
+			name:    "tables",
+			fixture: "tables.xml",
+			want: "Education\n" +
+				"Institution | Degree | Year\n" +
+				"Universitas Indonesia | S1 Informatika Cum laude | 2018\n" +
+				"\n" +
+				"Certifications",
+		},
+		{
+			name:    "header",
+			fixture: "header1.xml",
+			want:    "Jane Doe - Curriculum Vitae",
+		},
+		{
+			name:    "footer with tab stops",
+			fixture: "footer1.xml",
+			want:    "jane@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(filepath.Join("testdata", "docx", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			got, err := parseWordprocessingML(f, listStyles)
+			if err != nil {
+				t.Fatalf("parseWordprocessingML: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got:\n%q\nwant:\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDOCXListStyles(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "docx", "styles.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	got, err := parseDOCXListStyles(f)
+	if err != nil {
+		t.Fatalf("parseDOCXListStyles: %v", err)
+	}
+	want := map[string]int{"ListBullet": 0, "ListNumber": 1, "ListNumber2": 1}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for id, level := range want {
+		if l, ok := got[id]; !ok || l != level {
+			t.Errorf("style %s: got level %d (found %v), want %d", id, l, ok, level)
+		}
+	}
+}
+
+func TestExtractTextFromDOCX(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "docx", "resume.docx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := extractTextFromDOCX(data)
+	if err != nil {
+		t.Fatalf("extractTextFromDOCX: %v", err)
+	}
+	// Header duplikat (header1 / header2) hanya muncul sekali; list dari style di styles.xml
+	want := "Jane Doe - Curriculum Vitae\n\n" +
+		"Skills\n" +
+		"- Go\n" +
+		"  - Gin, GORM\n" +
+		"- MySQL\n" +
+		"- RabbitMQ\n" +
+		"  - Docker\n" +
+		"Not a list item\n" +
+		"Experience\n\n" +
+		"jane@example.com"
+	if got != want {
+		t.Errorf("got:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestExtractTextFromDOCXInvalid(t *testing.T) {
+	if _, err := extractTextFromDOCX([]byte("not a zip")); err == nil {
+		t.Error("expected error for non-zip data")
+	}
+}
@@ -0,0 +1,154 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"cv-evaluator/domain"
+)
+
+// Maksimal skill yang ditulis di prompt evaluasi
+const maxPromptSkillExperience = 15
+
+// SaveExperienceTimeline menghitung ulang timeline pengalaman dari posisi dan skill hasil
+// parsing CV sebuah upload, lalu mengganti hasil sebelumnya
+func SaveExperienceTimeline(db *gorm.DB, uploadID uint, now time.Time) (domain.ExperienceTimeline, error) {
+	var positions []domain.CVPosition
+	if err := db.Where("upload_id = ?", uploadID).Order("sort_order, id").Find(&positions).Error; err != nil {
+		return domain.ExperienceTimeline{}, fmt.Errorf("failed to load positions: %w", err)
+	}
+	var skills []domain.CVSkill
+	if err := db.Where("upload_id = ?", uploadID).Order("id").Find(&skills).Error; err != nil {
+		return domain.ExperienceTimeline{}, fmt.Errorf("failed to load skills: %w", err)
+	}
+
+	taxonomy, err := LoadSkillTaxonomy(db)
+	if err != nil {
+		return domain.ExperienceTimeline{}, err
+	}
+
+	analysis := domain.AnalyzeExperience(positions, skills, taxonomy, now)
+	timeline := domain.ExperienceTimeline{
+		UploadID:      uploadID,
+		TotalMonths:   analysis.TotalMonths,
+		PositionCount: analysis.PositionCount,
+		FirstStart:    analysis.FirstStart,
+		LastEnd:       analysis.LastEnd,
+		Current:       analysis.Current,
+		Gaps:          jsonArray(analysis.Gaps),
+		Overlaps:      jsonArray(analysis.Overlaps),
+		Issues:        jsonArray(analysis.Issues),
+		AnalyzedAt:    now,
+	}
+	for i := range analysis.Skills {
+		analysis.Skills[i].UploadID = uploadID
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteExperienceTimeline(tx, uploadID); err != nil {
+			return err
+		}
+		if err := tx.Create(&timeline).Error; err != nil {
+			return err
+		}
+		if len(analysis.Skills) > 0 {
+			if err := tx.Create(&analysis.Skills).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return timeline, err
+}
+
+func deleteExperienceTimeline(tx *gorm.DB, uploadID uint) error {
+	if err := tx.Where("upload_id = ?", uploadID).Delete(&domain.ExperienceTimeline{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("upload_id = ?", uploadID).Delete(&domain.SkillExperience{}).Error
+}
+
+// loadExperienceFacts menyusun timeline yang tersimpan jadi teks fakta untuk prompt.
+// Mengembalikan "" kalau CV upload ini belum / gagal di-parse.
+func loadExperienceFacts(db *gorm.DB, uploadID uint) (string, error) {
+	var timeline domain.ExperienceTimeline
+	err := db.Where("upload_id = ?", uploadID).First(&timeline).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var skills []domain.SkillExperience
+	if err := db.Where("upload_id = ?", uploadID).Order("months DESC, id").Find(&skills).Error; err != nil {
+		return "", err
+	}
+
+	// Posisi yang masih berjalan dihitung sampai hari ini, bukan sampai CV di-parse
+	now := time.Now()
+	timeline = timeline.At(now)
+	for i := range skills {
+		skills[i] = skills[i].At(timeline, now)
+	}
+	sort.SliceStable(skills, func(i, j int) bool { return skills[i].Months > skills[j].Months })
+	if len(skills) > maxPromptSkillExperience {
+		skills = skills[:maxPromptSkillExperience]
+	}
+	return formatExperienceFacts(timeline, skills), nil
+}
+
+// formatExperience untuk prompt; "(not available)" kalau CV belum di-parse
+func formatExperience(facts string) string {
+	if facts == "" {
+		return "(not available)"
+	}
+	return facts
+}
+
+func formatExperienceFacts(t domain.ExperienceTimeline, skills []domain.SkillExperience) string {
+	var b strings.Builder
+	if t.PositionCount == 0 {
+		b.WriteString("- No work position with a usable start date was found in the CV\n")
+	} else {
+		end := "present"
+		if !t.Current && t.LastEnd != nil {
+			end = t.LastEnd.Format("2006-01")
+		}
+		fmt.Fprintf(&b, "- Total professional experience: %.1f years (%d months, overlapping positions counted once) across %d dated positions, %s to %s\n",
+			domain.Years(t.TotalMonths), t.TotalMonths, t.PositionCount, t.FirstStart.Format("2006-01"), end)
+	}
+
+	if len(skills) > 0 {
+		parts := make([]string, 0, len(skills))
+		for _, s := range skills {
+			parts = append(parts, fmt.Sprintf("%s %.1f years", s.Name, domain.Years(s.Months)))
+		}
+		fmt.Fprintf(&b, "- Experience per skill (positions whose title or description mention it): %s\n", strings.Join(parts, "; "))
+	}
+
+	var gaps []domain.TimelineGap
+	_ = json.Unmarshal([]byte(t.Gaps), &gaps)
+	for _, g := range gaps {
+		fmt.Fprintf(&b, "- Employment gap: %s to %s (%d months)\n", g.From, g.To, g.Months)
+	}
+
+	var overlaps []domain.TimelineOverlap
+	_ = json.Unmarshal([]byte(t.Overlaps), &overlaps)
+	for _, o := range overlaps {
+		fmt.Fprintf(&b, "- Overlapping positions: %q and %q overlap for %d months\n", o.First, o.Second, o.Months)
+	}
+
+	var issues []domain.TimelineIssue
+	_ = json.Unmarshal([]byte(t.Issues), &issues)
+	for _, i := range issues {
+		fmt.Fprintf(&b, "- Inconsistent date in %q: %s\n", i.Position, i.Detail)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,177 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"cv-evaluator/domain"
+)
+
+// ErrLowQualityExtraction dikembalikan kalau teks hasil ekstraksi tidak layak dikirim ke model
+var ErrLowQualityExtraction = errors.New("extracted text quality is too low")
+
+// ExtractionResult adalah teks hasil ekstraksi beserta penilaian kualitasnya
+type ExtractionResult struct {
+	Text           string   `json:"-"`
+	Method         string   `json:"method"`          // extractor yang dipakai, mis. "unipdf", "gemini", "docx"
+	Model          string   `json:"model,omitempty"` // model Gemini kalau extractor-nya LLM
+	MIMEType       string   `json:"mime_type"`
+	Characters     int      `json:"characters"`
+	PrintableRatio float64  `json:"printable_ratio"`
+	PageCount      int      `json:"page_count,omitempty"`
+	EmptyPages     []int    `json:"empty_pages,omitempty"`  // halaman tanpa teks
+	FailedPages    []int    `json:"failed_pages,omitempty"` // halaman yang error saat diproses
+	ImagePages     []int    `json:"image_pages,omitempty"`  // halaman yang teksnya dibaca dari gambar (scan)
+	DurationMS     int64    `json:"duration_ms"`
+	Warnings       []string `json:"warnings,omitempty"`
+
+	Links []ExtractedLink `json:"links,omitempty"`
+	Info  *DocumentInfo   `json:"document_info,omitempty"`
+
+	Source *SourceAnalysis `json:"source_analysis,omitempty"` // metrik kalau file-nya source archive
+
+	HiddenText []domain.HiddenTextSpan `json:"hidden_text,omitempty"` // teks PDF yang tidak terlihat pembaca
+
+	skipTextLinks bool // extractor sudah mengumpulkan link sendiri
+}
+
+// newExtractionResult membuat result dan langsung menghitung statistik kualitas teks
+func newExtractionResult(method string, text string) *ExtractionResult {
+	r := &ExtractionResult{Method: method, Text: text}
+	r.assess()
+	return r
+}
+
+// assess menghitung jumlah karakter dan rasio karakter printable. Byte UTF-8 yang tidak
+// valid dan control character dihitung tidak printable (tanda teks sebenarnya binary).
+func (r *ExtractionResult) assess() {
+	total, printable := 0, 0
+	for i := 0; i < len(r.Text); {
+		ch, size := utf8.DecodeRuneInString(r.Text[i:])
+		i += size
+		total++
+		if ch == utf8.RuneError && size <= 1 {
+			continue
+		}
+		if unicode.IsPrint(ch) || unicode.IsSpace(ch) {
+			printable++
+		}
+	}
+
+	r.Characters = total
+	r.PrintableRatio = 0
+	if total > 0 {
+		r.PrintableRatio = float64(printable) / float64(total)
+	}
+}
+
+func (r *ExtractionResult) warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// QualityThresholds menentukan kapan hasil ekstraksi ditolak atau ditandai untuk dicek manual
+type QualityThresholds struct {
+	MinCharacters      int     // di bawah ini → ditolak
+	MinPrintableRatio  float64 // di bawah ini → ditolak
+	FlagPrintableRatio float64 // di bawah ini → diterima tapi ditandai
+}
+
+// LoadQualityThresholds baca EXTRACTION_MIN_CHARS, EXTRACTION_MIN_PRINTABLE_RATIO
+// dan EXTRACTION_FLAG_PRINTABLE_RATIO (default 100 / 0.85 / 0.95)
+func LoadQualityThresholds() QualityThresholds {
+	t := QualityThresholds{
+		MinCharacters:      100,
+		MinPrintableRatio:  0.85,
+		FlagPrintableRatio: 0.95,
+	}
+	if v, err := strconv.Atoi(os.Getenv("EXTRACTION_MIN_CHARS")); err == nil && v >= 0 {
+		t.MinCharacters = v
+	}
+	if v, err := strconv.ParseFloat(os.Getenv("EXTRACTION_MIN_PRINTABLE_RATIO"), 64); err == nil && v >= 0 && v <= 1 {
+		t.MinPrintableRatio = v
+	}
+	if v, err := strconv.ParseFloat(os.Getenv("EXTRACTION_FLAG_PRINTABLE_RATIO"), 64); err == nil && v >= 0 && v <= 1 {
+		t.FlagPrintableRatio = v
+	}
+	return t
+}
+
+// QualityThresholds mengembalikan threshold kualitas ekstraksi yang dipakai client ini
+func (g *GeminiClient) QualityThresholds() QualityThresholds {
+	return g.quality
+}
+
+// Acceptable cek apakah hasil ekstraksi cukup bagus untuk disimpan dan dievaluasi
+func (t QualityThresholds) Acceptable(r *ExtractionResult) error {
+	if r.Characters < t.MinCharacters {
+		return fmt.Errorf("%w: only %d characters extracted (minimum %d)", ErrLowQualityExtraction, r.Characters, t.MinCharacters)
+	}
+	if r.PrintableRatio < t.MinPrintableRatio {
+		return fmt.Errorf("%w: printable ratio %.2f is below %.2f (text looks like binary data)", ErrLowQualityExtraction, r.PrintableRatio, t.MinPrintableRatio)
+	}
+	return nil
+}
+
+// Flagged: diterima tapi perlu dicek manual (sebagian halaman kosong, banyak karakter aneh, dll)
+func (t QualityThresholds) Flagged(r *ExtractionResult) bool {
+	return r.PrintableRatio < t.FlagPrintableRatio || len(r.EmptyPages) > 0 || len(r.FailedPages) > 0 || len(r.Warnings) > 0
+}
+
+// textExtractor membungkus extractor yang hanya mengembalikan teks jadi TextExtractor
+func textExtractor(method string, fn func(data []byte) (string, error)) TextExtractor {
+	return func(data []byte) (*ExtractionResult, error) {
+		text, err := fn(data)
+		if err != nil {
+			return &ExtractionResult{Method: method}, err
+		}
+		return newExtractionResult(method, text), nil
+	}
+}
+
+// Metadata mengubah result jadi record provenance yang disimpan per dokumen
+func (r *ExtractionResult) Metadata(kind string) domain.ExtractionMetadata {
+	meta := domain.ExtractionMetadata{
+		Kind:           kind,
+		Extractor:      r.Method,
+		Model:          r.Model,
+		MimeType:       r.MIMEType,
+		PageCount:      r.PageCount,
+		EmptyPages:     jsonArray(r.EmptyPages),
+		FailedPages:    jsonArray(r.FailedPages),
+		ImagePages:     jsonArray(r.ImagePages),
+		CharacterCount: r.Characters,
+		PrintableRatio: r.PrintableRatio,
+		DurationMS:     r.DurationMS,
+		Warnings:       jsonArray(r.Warnings),
+		HiddenText:     jsonArray(r.HiddenText),
+	}
+	if r.Info != nil {
+		// Info dictionary ditulis pembuat file, panjangnya tidak dibatasi: dipotong sesuai kolom
+		meta.DocumentTitle = clip(r.Info.Title, 512)
+		meta.DocumentAuthor = clip(r.Info.Author, 255)
+		meta.DocumentCreator = clip(r.Info.Creator, 255)
+		meta.DocumentProducer = clip(r.Info.Producer, 255)
+		meta.DocumentCreatedAt = r.Info.CreatedAt
+		meta.DocumentModifiedAt = r.Info.ModifiedAt
+	}
+	if r.Source != nil {
+		b, _ := json.Marshal(r.Source)
+		analysis := string(b)
+		meta.SourceAnalysis = &analysis
+	}
+	return meta
+}
+
+// jsonArray selalu menghasilkan JSON array (nil jadi "[]") untuk kolom bertipe json
+func jsonArray[T any](v []T) string {
+	if v == nil {
+		v = []T{}
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
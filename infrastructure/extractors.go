@@ -0,0 +1,105 @@
+package infrastructure
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// MIME types yang dipakai sebagai key registry
+const (
+	MIMEPlainText = "text/plain"
+	MIMEMarkdown  = "text/markdown"
+	MIMEHTML      = "text/html"
+	MIMERTF       = "text/rtf"
+	MIMEPDF       = "application/pdf"
+	MIMEDOCX      = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	MIMEODT       = "application/vnd.oasis.opendocument.text"
+	MIMEPNG       = "image/png"
+	MIMEJPEG      = "image/jpeg"
+	MIMEWebP      = "image/webp"
+)
+
+// TextExtractor mengubah isi file jadi plain text beserta penilaian kualitasnya
+type TextExtractor func(data []byte) (*ExtractionResult, error)
+
+// ExtractorRegistry memetakan MIME type (hasil sniffing isi file) ke extractor
+type ExtractorRegistry struct {
+	extractors map[string]TextExtractor
+}
+
+func NewExtractorRegistry() *ExtractorRegistry {
+	return &ExtractorRegistry{extractors: map[string]TextExtractor{}}
+}
+
+// Register menambahkan extractor untuk satu MIME type
+func (r *ExtractorRegistry) Register(mimeType string, extractor TextExtractor) {
+	r.extractors[mimeType] = extractor
+}
+
+// Has cek apakah ada extractor untuk MIME type ini
+func (r *ExtractorRegistry) Has(mimeType string) bool {
+	_, ok := r.extractors[mimeType]
+	return ok
+}
+
+// Types mengembalikan semua MIME type yang terdaftar (urut alfabet)
+func (r *ExtractorRegistry) Types() []string {
+	types := make([]string, 0, len(r.extractors))
+	for m := range r.extractors {
+		types = append(types, m)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Lookup mencari extractor untuk kandidat MIME type hasil DetectMIME (dari yang paling
+// spesifik). Kalau tidak ada yang cocok persis, parent type-nya yang dipakai
+// (mis. format berbasis teks jatuh ke text/plain).
+func (r *ExtractorRegistry) Lookup(candidates []string) (string, TextExtractor, bool) {
+	for _, m := range candidates {
+		if ext, ok := r.extractors[m]; ok {
+			return m, ext, true
+		}
+	}
+	return "", nil, false
+}
+
+// DetectMIME sniff MIME type dari isi file dan mengembalikan type tersebut beserta
+// parent-nya. Markdown tidak punya magic bytes, jadi teks apa pun (termasuk yang tersniff
+// sebagai text/html, mis. README yang diawali <p align="center">) dengan ekstensi
+// .md / .markdown dianggap text/markdown.
+func DetectMIME(data []byte, filename string) []string {
+	mtype := mimetype.Detect(data)
+
+	var candidates []string
+	if isMarkdownFilename(filename) && isTextMIME(mtype) {
+		candidates = append(candidates, MIMEMarkdown)
+	}
+	for m := mtype; m != nil; m = m.Parent() {
+		candidates = append(candidates, baseMIME(m.String()))
+	}
+	return candidates
+}
+
+// isTextMIME: text/* atau turunan text/plain
+func isTextMIME(mtype *mimetype.MIME) bool {
+	return strings.HasPrefix(baseMIME(mtype.String()), "text/") || mtype.Is(MIMEPlainText)
+}
+
+func isMarkdownFilename(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+func baseMIME(m string) string {
+	if i := strings.Index(m, ";"); i != -1 {
+		m = m[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(m))
+}
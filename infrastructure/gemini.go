@@ -7,18 +7,31 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"mime/multipart"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
-	"github.com/unidoc/unipdf/v3/extractor"
 	"github.com/unidoc/unipdf/v3/model"
+
+	"cv-evaluator/domain"
 )
 
 type GeminiClient struct {
-	apiKey string
+	apiKey     string
+	extractors *ExtractorRegistry
+	quality    QualityThresholds
+
+	sourceTokens int // token budget isi file dari source archive
+	promptTokens int // token budget prompt evaluasi / perbandingan / parsing CV
+}
+
+// CandidateDocuments is the extracted text of one candidate's submission
+type CandidateDocuments struct {
+	CVText      string
+	ProjectText string
+	Links       []domain.DocumentLink // GitHub, LinkedIn, portfolio, ... dari kedua dokumen
+	Experience  string                // fakta timeline pengalaman dari CV yang sudah di-parse
 }
 
 // NewGeminiClient creates a new Gemini client
@@ -27,124 +40,228 @@ func NewGeminiClient() *GeminiClient {
 	if apiKey == "" {
 		panic("GEMINI_API_KEY environment variable not set")
 	}
-	return &GeminiClient{apiKey: apiKey}
+	g := &GeminiClient{
+		apiKey:       apiKey,
+		quality:      LoadQualityThresholds(),
+		sourceTokens: LoadSourceTokenBudget(),
+		promptTokens: LoadPromptTokenBudget(),
+	}
+	g.extractors = g.newExtractorRegistry()
+	return g
+}
+
+// newExtractorRegistry registers the text extractor for every supported document format
+func (g *GeminiClient) newExtractorRegistry() *ExtractorRegistry {
+	r := NewExtractorRegistry()
+	r.Register(MIMEPlainText, textExtractor("plain", func(data []byte) (string, error) { return string(data), nil }))
+	r.Register(MIMEMarkdown, textExtractor("markdown", extractTextFromMarkdown))
+	r.Register(MIMEHTML, textExtractor("html", extractTextFromHTML))
+	r.Register(MIMERTF, textExtractor("rtf", extractTextFromRTF))
+	r.Register(MIMEPDF, g.extractTextFromPDFWithFallback)
+	r.Register(MIMEDOCX, textExtractor("docx", extractTextFromDOCX))
+	r.Register(MIMEODT, textExtractor("odt", extractTextFromODT))
+	r.Register(MIMEPNG, g.imageExtractor(MIMEPNG))
+	r.Register(MIMEJPEG, g.imageExtractor(MIMEJPEG))
+	r.Register(MIMEWebP, g.imageExtractor(MIMEWebP))
+	r.Register(MIMEZip, g.extractSourceArchive)
+	r.Register(MIMEGzip, g.extractSourceArchive)
+	return r
 }
 
-// ExtractTextFromFile extracts text from files including PDF
-func (g *GeminiClient) ExtractTextFromFile(file multipart.File, filename string) (string, error) {
-	data, err := io.ReadAll(file)
+// ExtractText extracts text from a validated upload, choosing the extractor by the
+// MIME type sniffed from its content. The result carries a quality assessment; callers
+// decide whether to accept it (see QualityThresholds). When the extractor fails the
+// partial result (extractor, model, warnings, duration) is returned with the error so the
+// attempt can still be recorded.
+func (g *GeminiClient) ExtractText(doc *UploadedDocument) (*ExtractionResult, error) {
+	_, extract, ok := g.extractors.Lookup([]string{doc.MIMEType})
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, doc.MIMEType)
+	}
+	fmt.Printf("Extracting %s as %s\n", doc.Filename, doc.MIMEType)
+
+	start := time.Now()
+	result, err := extract(doc.Data)
+	if result == nil {
+		result = &ExtractionResult{}
+	}
+	result.MIMEType = doc.MIMEType
+	result.DurationMS = time.Since(start).Milliseconds()
 	if err != nil {
-		return "", fmt.Errorf("failed to read file: %w", err)
-	}
-
-	fileExtension := strings.ToLower(filename[strings.LastIndex(filename, ".")+1:])
-
-	switch fileExtension {
-	case "txt":
-		// Handle text files directly
-		return string(data), nil
-	case "pdf":
-		// Handle PDF files with multiple fallback methods
-		return g.extractTextFromPDFWithFallback(data)
-	default:
-		// For other file types, try to extract as much text as possible
-		if len(data) > 10000 {
-			data = data[:10000] // Truncate very long content
-		}
-		return string(data), nil
+		return result, err
 	}
+	result.collectTextLinks()
+	return result, nil
 }
 
-// extractTextFromPDFWithFallback tries multiple methods to extract text from PDF
-func (g *GeminiClient) extractTextFromPDFWithFallback(data []byte) (string, error) {
+// extractTextFromPDFWithFallback tries unipdf first and falls back to Gemini when
+// unipdf fails or its output is below the quality thresholds
+func (g *GeminiClient) extractTextFromPDFWithFallback(data []byte) (*ExtractionResult, error) {
 	// Method 1: Try standard PDF text extraction
-	text, err := g.extractTextFromPDF(data)
-	if err == nil && text != "" {
-		return text, nil
+	result, err := g.extractTextFromPDF(data)
+	if err == nil && g.quality.Acceptable(result) == nil && len(result.EmptyPages) == 0 && len(result.FailedPages) == 0 {
+		return result, nil
 	}
 
 	// Method 2: Try Gemini API for PDF text extraction
-	fmt.Println("Standard PDF extraction failed, trying Gemini API...")
-	geminiText, err := g.extractTextFromPDFWithGemini(data)
-	if err == nil && geminiText != "" {
-		return geminiText, nil
+	if err != nil {
+		fmt.Printf("Standard PDF extraction failed (%v), trying Gemini API...\n", err)
+	} else {
+		fmt.Println("Standard PDF extraction is incomplete or low quality, trying Gemini API...")
+	}
+	geminiText, geminiModel, geminiErr := g.extractTextFromPDFWithGemini(data)
+	if geminiErr == nil {
+		geminiResult := newExtractionResult("gemini", normalizeText(geminiText))
+		geminiResult.Model = geminiModel
+		if result != nil {
+			geminiResult.PageCount = result.PageCount
+			geminiResult.Links = result.Links
+			geminiResult.Info = result.Info
+			geminiResult.HiddenText = result.HiddenText
+			geminiResult.warn("unipdf output was incomplete (empty pages: %v, failed pages: %v), used Gemini instead", result.EmptyPages, result.FailedPages)
+		}
+		if result == nil || g.quality.Acceptable(geminiResult) == nil {
+			return geminiResult, nil
+		}
 	}
 
-	// Method 3: Return raw data as string (fallback)
-	fmt.Println("All extraction methods failed, returning raw data...")
-	if len(data) > 5000 {
-		return string(data[:5000]), nil
+	// Jangan pernah kembalikan raw bytes PDF sebagai teks: pakai hasil unipdf apa adanya
+	// (caller yang menolak kalau kualitasnya di bawah threshold) atau gagal sama sekali
+	if result != nil {
+		if geminiErr != nil {
+			result.warn("Gemini fallback failed: %v", geminiErr)
+		}
+		return result, nil
 	}
-	return string(data), nil
+	failed := &ExtractionResult{Method: "unipdf", Model: geminiModel}
+	failed.warn("unipdf failed: %v", err)
+	failed.warn("Gemini fallback failed: %v", geminiErr)
+	return failed, fmt.Errorf("failed to extract text from PDF: %v; Gemini fallback: %w", err, geminiErr)
 }
 
 // extractTextFromPDF extracts text from PDF files using unipdf
-func (g *GeminiClient) extractTextFromPDF(data []byte) (string, error) {
+func (g *GeminiClient) extractTextFromPDF(data []byte) (*ExtractionResult, error) {
 	// Create a PDF reader from byte data
 	pdfReader, err := model.NewPdfReader(bytes.NewReader(data))
 	if err != nil {
-		return "", fmt.Errorf("failed to read PDF: %w", err)
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
 	}
 
 	// Get number of pages
 	numPages, err := pdfReader.GetNumPages()
 	if err != nil {
-		return "", fmt.Errorf("failed to get page count: %w", err)
+		return nil, fmt.Errorf("failed to get page count: %w", err)
 	}
 
 	if numPages == 0 {
-		return "", fmt.Errorf("PDF has no pages")
+		return nil, fmt.Errorf("PDF has no pages")
 	}
 
-	var textBuilder strings.Builder
-	extractedAnyText := false
+	pageTexts := make([]string, numPages+1) // index = nomor halaman
+	var emptyPages, failedPages []int
+	var warnings []string
+	columnPages := 0
+	pageLinks := map[int][]string{}
+	var hidden []domain.HiddenTextSpan
 
 	// Extract text from each page
 	for i := 1; i <= numPages; i++ {
 		page, err := pdfReader.GetPage(i)
 		if err != nil {
 			fmt.Printf("Error getting page %d: %v\n", i, err)
+			warnings = append(warnings, fmt.Sprintf("page %d: %v", i, err))
+			failedPages = append(failedPages, i)
 			continue // Skip pages with errors
 		}
+		pageLinks[i] = extractPDFLinks(page)
+		hidden = append(hidden, extractPDFHiddenText(page, i)...)
 
-		ex, err := extractor.New(page)
-		if err != nil {
-			fmt.Printf("Error creating extractor for page %d: %v\n", i, err)
-			continue
-		}
-
-		pageText, err := ex.ExtractText()
+		pageText, columns, err := extractPDFPageText(page)
 		if err != nil {
 			fmt.Printf("Error extracting text from page %d: %v\n", i, err)
+			warnings = append(warnings, fmt.Sprintf("page %d: %v", i, err))
+			failedPages = append(failedPages, i)
 			continue
 		}
+		if columns {
+			columnPages++
+		}
 
-		if pageText != "" {
-			extractedAnyText = true
-			textBuilder.WriteString(fmt.Sprintf("--- Page %d ---\n", i))
-			textBuilder.WriteString(pageText)
-			textBuilder.WriteString("\n\n")
+		if strings.TrimSpace(pageText) != "" {
+			pageTexts[i] = pageText
 		} else {
 			fmt.Printf("No text found on page %d\n", i)
+			emptyPages = append(emptyPages, i)
 		}
 	}
 
-	result := strings.TrimSpace(textBuilder.String())
-	if !extractedAnyText {
-		return "", fmt.Errorf("no text could be extracted from any page of the PDF")
+	// Halaman tanpa text layer (hasil scan) dibaca dari gambarnya
+	method, visionModel := "unipdf", ""
+	var imagePages []int
+	if len(emptyPages) > 0 {
+		scanned, scanModel, scanWarnings := g.extractScannedPages(pdfReader, emptyPages)
+		warnings = append(warnings, scanWarnings...)
+
+		var stillEmpty []int
+		for _, num := range emptyPages {
+			if text := scanned[num]; strings.TrimSpace(text) != "" {
+				pageTexts[num] = text
+				imagePages = append(imagePages, num)
+			} else {
+				stillEmpty = append(stillEmpty, num)
+			}
+		}
+		emptyPages = stillEmpty
+
+		if len(imagePages) > 0 {
+			visionModel = scanModel
+			method = "unipdf+" + visionMethod
+			if len(imagePages)+len(emptyPages)+len(failedPages) == numPages {
+				method = visionMethod
+			}
+			fmt.Printf("Extracted %d scanned pages from page images\n", len(imagePages))
+		}
 	}
 
-	fmt.Printf("Successfully extracted text from PDF (%d characters)\n", len(result))
-	return result, nil
-}
+	if len(emptyPages)+len(failedPages) == numPages {
+		return nil, fmt.Errorf("no text could be extracted from any page of the PDF")
+	}
+	if columnPages > 0 {
+		fmt.Printf("Detected two-column layout on %d of %d pages\n", columnPages, numPages)
+	}
 
-// extractTextFromPDFWithGemini uses Gemini API to extract text from PDF
-func (g *GeminiClient) extractTextFromPDFWithGemini(data []byte) (string, error) {
-	ctx := context.Background()
+	var pages []string
+	for _, text := range pageTexts {
+		if text != "" {
+			pages = append(pages, text)
+		}
+	}
 
-	// Encode PDF to base64
-	encodedPDF := base64.StdEncoding.EncodeToString(data)
+	// Header/footer, nomor halaman, hyphenation dan line break dibersihkan sebelum dinilai
+	result := newExtractionResult(method, strings.TrimSpace(normalizePDFPages(pages)))
+	result.Model = visionModel
+	result.PageCount = numPages
+	result.ImagePages = imagePages
+	result.EmptyPages = emptyPages
+	result.FailedPages = failedPages
+	result.Warnings = warnings
+	result.Info = extractPDFInfo(pdfReader)
+	result.HiddenText = hidden
+	if len(hidden) > 0 {
+		result.warn("%d hidden text span(s) found (white, tiny or off-page text)", len(hidden))
+	}
+	for i := 1; i <= numPages; i++ {
+		for _, link := range pageLinks[i] {
+			result.addLink(link, domain.LinkSourceAnnotation, i)
+		}
+	}
+
+	fmt.Printf("Successfully extracted text from PDF (%d characters)\n", result.Characters)
+	return result, nil
+}
 
+// extractTextFromPDFWithGemini uses Gemini API to extract text from PDF and returns the model that succeeded
+func (g *GeminiClient) extractTextFromPDFWithGemini(data []byte) (string, string, error) {
 	prompt := `Extract ALL text content from this PDF document. Return ONLY the raw extracted text without any additional comments, formatting, or explanations. Include:
 
 - Personal information (name, email, phone)
@@ -156,20 +273,36 @@ func (g *GeminiClient) extractTextFromPDFWithGemini(data []byte) (string, error)
 
 Return the text exactly as it appears in the document.`
 
+	return g.generateTextFromInlineData(context.Background(), prompt, []inlineData{{MIMEType: MIMEPDF, Data: data}}, "PDF extraction")
+}
+
+// inlineData adalah file (PDF / gambar) yang dikirim langsung di request sebagai base64
+type inlineData struct {
+	MIMEType string
+	Data     []byte
+}
+
+// generateTextFromInlineData sends the prompt together with the files as inline_data parts
+// and returns the plain text response and the model that succeeded
+func (g *GeminiClient) generateTextFromInlineData(ctx context.Context, prompt string, files []inlineData, purpose string) (string, string, error) {
+	parts := []map[string]interface{}{
+		{
+			"text": prompt,
+		},
+	}
+	for _, f := range files {
+		parts = append(parts, map[string]interface{}{
+			"inline_data": map[string]interface{}{
+				"mime_type": f.MIMEType,
+				"data":      base64.StdEncoding.EncodeToString(f.Data),
+			},
+		})
+	}
+
 	requestBody := map[string]interface{}{
 		"contents": []map[string]interface{}{
 			{
-				"parts": []map[string]interface{}{
-					{
-						"text": prompt,
-					},
-					{
-						"inline_data": map[string]interface{}{
-							"mime_type": "application/pdf",
-							"data":      encodedPDF,
-						},
-					},
-				},
+				"parts": parts,
 			},
 		},
 		"generationConfig": map[string]interface{}{
@@ -180,7 +313,7 @@ Return the text exactly as it appears in the document.`
 
 	jsonData, err := json.Marshal(requestBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return "", "", fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	// Try available models
@@ -193,7 +326,7 @@ Return the text exactly as it appears in the document.`
 
 	var lastError error
 	for _, model := range availableModels {
-		fmt.Printf("Trying Gemini model for PDF extraction: %s\n", model)
+		fmt.Printf("Trying Gemini model for %s: %s\n", purpose, model)
 
 		url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s",
 			model, g.apiKey)
@@ -206,15 +339,15 @@ Return the text exactly as it appears in the document.`
 
 		req.Header.Set("Content-Type", "application/json")
 
-		client := &http.Client{Timeout: 120 * time.Second} // Longer timeout for PDF processing
+		client := &http.Client{Timeout: 120 * time.Second} // Longer timeout for PDF / image processing
 		resp, err := client.Do(req)
 		if err != nil {
 			lastError = err
 			continue
 		}
-		defer resp.Body.Close()
 
 		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
 		if err != nil {
 			lastError = err
 			continue
@@ -238,18 +371,49 @@ Return the text exactly as it appears in the document.`
 		}
 
 		if text != "" {
-			fmt.Printf("Successfully extracted text from PDF using Gemini (%d characters)\n", len(text))
-			return strings.TrimSpace(text), nil
+			fmt.Printf("Successfully completed %s using Gemini (%d characters)\n", purpose, len(text))
+			return strings.TrimSpace(text), model, nil
 		}
 	}
 
-	return "", fmt.Errorf("all Gemini models failed for PDF extraction: %w", lastError)
+	return "", "", fmt.Errorf("all Gemini models failed for %s: %w", purpose, lastError)
+}
+
+// Evaluate sends a rendered evaluation prompt (see RenderEvaluationPrompt) to Gemini
+func (g *GeminiClient) Evaluate(ctx context.Context, prompt string) (map[string]interface{}, error) {
+	return g.generateJSONWithFallback(ctx, prompt, "evaluation")
 }
 
-// Evaluate performs evaluation using Gemini API
-func (g *GeminiClient) Evaluate(ctx context.Context, description string, rubric string, cv string, project string) (map[string]interface{}, error) {
+// generateJSONWithFallback sends the prompt to each available model in turn until one returns valid JSON
+func (g *GeminiClient) generateJSONWithFallback(ctx context.Context, prompt string, purpose string) (map[string]interface{}, error) {
+	tokens := domain.EstimateTokens(prompt)
+
+	var lastError error
+	for _, model := range jsonModels {
+		// Model yang limit input-nya tidak cukup langsung dilewati, tidak perlu menunggu ditolak API
+		if limit := modelInputLimit(model); tokens+promptOutputReserve > limit {
+			lastError = fmt.Errorf("prompt of ~%d tokens exceeds the input limit of %s (%d tokens)", tokens, model, limit)
+			fmt.Printf("Skipping model %s for %s: %v\n", model, purpose, lastError)
+			continue
+		}
+		fmt.Printf("Trying model for %s: %s (~%d tokens)\n", purpose, model, tokens)
+		response, err := g.callGeminiWithModel(ctx, prompt, model)
+		if err == nil {
+			fmt.Printf("Success with model: %s\n", model)
+			return response, nil
+		}
+		lastError = err
+		fmt.Printf("Model %s failed: %v\n", model, err)
+	}
+
+	return nil, fmt.Errorf("all models failed: %w", lastError)
+}
+
+// Compare asks the model which of two candidates better fits the job and rubric
+func (g *GeminiClient) Compare(ctx context.Context, description string, rubric string, a CandidateDocuments, b CandidateDocuments) (map[string]interface{}, error) {
+	nonce := newPromptNonce()
 	prompt := fmt.Sprintf(
-		`You are an evaluator. Use the following job description and rubric to evaluate:
+		`You are an evaluator comparing two candidates for the same job. Use the following job description and rubric:
 
 Job Description:
 %s
@@ -257,64 +421,54 @@ Job Description:
 Rubric:
 %s
 
-CV Input:
+Candidate A CV:
 %s
 
-Project Input:
+Candidate A Project:
 %s
 
-Define at least these scoring parameters:
- CV Evaluation (Match Rate)
- Technical Skills Match (backend, databases, APIs, cloud, AI/LLM exposure).
- Experience Level (years, project complexity).
- Relevant Achievements (impact, scale).
- Cultural Fit (communication, learning attitude).
- Project Deliverable Evaluation
- 
- Correctness (meets requirements: prompt design, chaining, RAG, handling errors).
- Code Quality (clean, modular, testable).
- Resilience (handles failures, retries).
- Documentation (clear README, explanation of trade-offs).
- Creativity / Bonus (optional improvements like authentication, deployment, dashboards).
- Each parameter can be scored 1–5, then aggregated to final score
+Candidate A Links:
+%s
+
+Candidate A Verified Experience Facts:
+%s
+
+Candidate B CV:
+%s
+
+Candidate B Project:
+%s
+
+Candidate B Links:
+%s
+
+Candidate B Verified Experience Facts:
+%s
+
+%s
+
+Personal details may be replaced with placeholders such as [A_NAME_1], [B_EMAIL_1] or [A_GENDER_1]; the A_ / B_ prefix tells which
+candidate the value belongs to. This is intentional: do not try to infer the hidden values and do not let them affect the judgement.
+
+Judge which candidate is the better overall fit, considering both the CV (technical skills, experience, achievements, cultural fit)
+and the project deliverable (correctness, code quality, resilience, documentation, creativity).
+A project that starts with a "Source Archive Analysis" section is submitted source code; use its offline metrics and included files as evidence.
+Do not let the order in which the candidates are presented influence your decision.
 
 Return strict JSON with structure:
 {
-  "cv": {
-    "match_rate": float,
-    "feedback": string
-  },
-  "project": {
-    "score": float,
-    "feedback": string
-  },
-  "overall_summary": string
+  "winner": "A" | "B" | "tie",
+  "confidence": float,
+  "reasoning": string
 }
 
-IMPORTANT: cv match_rate is between 0-1 and project score is between 1-10 and Return ONLY the raw JSON without any markdown formatting, code blocks, or additional text.`, description, rubric, cv, project)
-
-	// Use the available models from your API
-	availableModels := []string{
-		"gemini-2.0-flash-001",
-		"gemini-2.0-flash",
-		"gemini-2.5-flash",
-		"gemini-2.5-flash-preview-09-2025",
-		"gemini-flash-latest",
-	}
-
-	var lastError error
-	for _, model := range availableModels {
-		fmt.Printf("Trying model for evaluation: %s\n", model)
-		response, err := g.callGeminiWithModel(ctx, prompt, model)
-		if err == nil {
-			fmt.Printf("Success with model: %s\n", model)
-			return response, nil
-		}
-		lastError = err
-		fmt.Printf("Model %s failed: %v\n", model, err)
-	}
+IMPORTANT: confidence is between 0-1 and Return ONLY the raw JSON without any markdown formatting, code blocks, or additional text.`,
+		description, rubric,
+		untrustedBlock("CV_A", nonce, a.CVText), untrustedBlock("PROJECT_A", nonce, a.ProjectText), untrustedBlock("LINKS_A", nonce, formatLinks(a.Links)), formatExperience(a.Experience),
+		untrustedBlock("CV_B", nonce, b.CVText), untrustedBlock("PROJECT_B", nonce, b.ProjectText), untrustedBlock("LINKS_B", nonce, formatLinks(b.Links)), formatExperience(b.Experience),
+		untrustedContentRules(nonce))
 
-	return nil, fmt.Errorf("all models failed: %w", lastError)
+	return g.generateJSONWithFallback(ctx, prompt, "comparison")
 }
 
 func (g *GeminiClient) callGeminiWithModel(ctx context.Context, prompt string, model string) (map[string]interface{}, error) {
@@ -0,0 +1,130 @@
+package infrastructure
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+)
+
+// Fungsi dengan cyclomatic complexity di atas ini dianggap terlalu kompleks
+const goComplexityThreshold = 10
+
+// GoComplexity adalah ringkasan cyclomatic complexity semua fungsi Go (non-test) di archive
+type GoComplexity struct {
+	Functions     int                  `json:"functions"`
+	Average       float64              `json:"average"`
+	Max           int                  `json:"max"`
+	Threshold     int                  `json:"threshold"`
+	OverThreshold int                  `json:"over_threshold"` // jumlah fungsi dengan complexity > threshold
+	ParseErrors   int                  `json:"parse_errors"`   // file .go yang tidak bisa di-parse
+	Top           []FunctionComplexity `json:"top"`            // fungsi paling kompleks
+}
+
+// FunctionComplexity adalah complexity satu fungsi / method
+type FunctionComplexity struct {
+	Function   string `json:"function"` // "Handler.Upload" untuk method
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Complexity int    `json:"complexity"`
+}
+
+// goFileComplexity mem-parse satu file Go dan menghitung complexity setiap fungsinya
+func goFileComplexity(fset *token.FileSet, filename string, src []byte) ([]FunctionComplexity, error) {
+	file, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	var funcs []FunctionComplexity
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		funcs = append(funcs, FunctionComplexity{
+			Function:   goFuncName(fn),
+			File:       filename,
+			Line:       fset.Position(fn.Pos()).Line,
+			Complexity: cyclomaticComplexity(fn.Body),
+		})
+	}
+	return funcs, nil
+}
+
+// cyclomaticComplexity = 1 + jumlah titik percabangan: if, for, range, case / select case
+// (selain default), && dan ||. Function literal di dalamnya dihitung ke fungsi induknya.
+func cyclomaticComplexity(body *ast.BlockStmt) int {
+	complexity := 1
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if n.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+	return complexity
+}
+
+func goFuncName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	typ := fn.Recv.List[0].Type
+	for {
+		switch t := typ.(type) {
+		case *ast.StarExpr:
+			typ = t.X
+			continue
+		case *ast.IndexExpr: // receiver generic: T[K]
+			typ = t.X
+			continue
+		case *ast.IndexListExpr:
+			typ = t.X
+			continue
+		case *ast.Ident:
+			return t.Name + "." + fn.Name.Name
+		}
+		return fn.Name.Name
+	}
+}
+
+// summarizeGoComplexity menghitung rata-rata, maksimum dan 5 fungsi paling kompleks
+func summarizeGoComplexity(funcs []FunctionComplexity, parseErrors int) *GoComplexity {
+	c := &GoComplexity{Functions: len(funcs), Threshold: goComplexityThreshold, ParseErrors: parseErrors}
+	if len(funcs) == 0 {
+		return c
+	}
+
+	total := 0
+	for _, f := range funcs {
+		total += f.Complexity
+		if f.Complexity > c.Max {
+			c.Max = f.Complexity
+		}
+		if f.Complexity > goComplexityThreshold {
+			c.OverThreshold++
+		}
+	}
+	c.Average = float64(total) / float64(len(funcs))
+
+	sorted := append([]FunctionComplexity(nil), funcs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Complexity > sorted[j].Complexity })
+	if len(sorted) > 5 {
+		sorted = sorted[:5]
+	}
+	c.Top = sorted
+	return c
+}
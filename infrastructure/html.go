@@ -0,0 +1,173 @@
+package infrastructure
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Elemen yang isinya tidak pernah dianggap teks dokumen
+var htmlSkipElements = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true,
+	"head": true, "svg": true, "iframe": true, "object": true,
+}
+
+// Elemen block: teks di dalamnya ditulis di baris sendiri
+var htmlBlockElements = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "header": true,
+	"footer": true, "main": true, "aside": true, "nav": true, "blockquote": true,
+	"pre": true, "ul": true, "ol": true, "dl": true, "dt": true, "dd": true,
+	"table": true, "form": true, "fieldset": true, "address": true, "figure": true,
+	"figcaption": true, "hr": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// extractTextFromHTML converts an HTML document to plain text: headings and
+// paragraphs on their own lines, list items prefixed with "- ", table rows as "a | b"
+func extractTextFromHTML(data []byte) (string, error) {
+	doc, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	w := &htmlTextWriter{}
+	w.walk(doc, 0)
+
+	lines := strings.Split(w.String(), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t")
+	}
+	text := collapseBlankLines(lines)
+	if text == "" {
+		return "", fmt.Errorf("no text found in HTML")
+	}
+
+	fmt.Printf("Successfully extracted text from HTML (%d characters)\n", len(text))
+	return text, nil
+}
+
+type htmlTextWriter struct {
+	strings.Builder
+	inPre bool
+}
+
+func (w *htmlTextWriter) newline() {
+	s := w.String()
+	if s != "" && !strings.HasSuffix(s, "\n") {
+		w.WriteString("\n")
+	}
+}
+
+func (w *htmlTextWriter) walk(n *html.Node, listDepth int) {
+	switch n.Type {
+	case html.TextNode:
+		if w.inPre {
+			w.WriteString(n.Data)
+			return
+		}
+		text := strings.Join(strings.Fields(n.Data), " ")
+		if text == "" {
+			// Whitespace antar inline element tetap jadi satu spasi
+			if n.Data != "" && !strings.HasSuffix(w.String(), " ") && !strings.HasSuffix(w.String(), "\n") {
+				w.WriteString(" ")
+			}
+			return
+		}
+		if startsWithSpace(n.Data) && !strings.HasSuffix(w.String(), " ") && !strings.HasSuffix(w.String(), "\n") {
+			w.WriteString(" ")
+		}
+		w.WriteString(text)
+		if endsWithSpace(n.Data) {
+			w.WriteString(" ")
+		}
+		return
+	case html.ElementNode:
+		if htmlSkipElements[n.Data] {
+			return
+		}
+	case html.CommentNode, html.DoctypeNode:
+		return
+	}
+
+	tag := ""
+	if n.Type == html.ElementNode {
+		tag = n.Data
+	}
+
+	switch tag {
+	case "br":
+		w.WriteString("\n")
+		return
+	case "ul", "ol":
+		listDepth++
+	case "li":
+		w.newline()
+		w.WriteString(strings.Repeat("  ", max(listDepth-1, 0)) + "- ")
+	case "tr":
+		w.newline()
+	case "td", "th":
+		if !strings.HasSuffix(w.String(), "\n") && w.Len() > 0 {
+			w.WriteString(" | ")
+		}
+	case "pre":
+		w.inPre = true
+	case "img":
+		if alt := htmlAttr(n, "alt"); alt != "" {
+			w.WriteString(alt)
+		}
+	}
+
+	if htmlBlockElements[tag] {
+		w.newline()
+		if isHeading(tag) {
+			w.WriteString("\n")
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.walk(c, listDepth)
+	}
+
+	switch tag {
+	case "a":
+		// Link eksternal ditulis setelah teksnya supaya URL profil kandidat tidak hilang
+		href := htmlAttr(n, "href")
+		if strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://") {
+			w.WriteString(" (" + href + ")")
+		}
+	case "pre":
+		w.inPre = false
+	case "li", "tr":
+		w.newline()
+	}
+
+	if htmlBlockElements[tag] {
+		w.newline()
+		if isHeading(tag) || tag == "p" {
+			w.WriteString("\n")
+		}
+	}
+}
+
+func htmlAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+func isHeading(tag string) bool {
+	return len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6'
+}
+
+func startsWithSpace(s string) bool {
+	return s != "" && strings.ContainsRune(" \t\r\n", rune(s[0]))
+}
+
+func endsWithSpace(s string) bool {
+	return s != "" && strings.ContainsRune(" \t\r\n", rune(s[len(s)-1]))
+}
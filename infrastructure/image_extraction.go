@@ -0,0 +1,186 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/jpeg"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/unidoc/unipdf/v3/extractor"
+	"github.com/unidoc/unipdf/v3/model"
+)
+
+const (
+	// Extractor untuk teks yang dibaca model dari gambar (upload gambar / halaman hasil scan)
+	visionMethod = "gemini-vision"
+	// Jumlah halaman hasil scan yang dikirim dalam satu request
+	imagePagesPerRequest = 4
+	// Batas total ukuran gambar per request (limit inline_data Gemini 20 MB setelah base64)
+	maxImageBatchBytes = 12 << 20
+	// Gambar dianggap scan satu halaman kalau menutupi minimal porsi halaman ini
+	minPageImageCoverage = 0.3
+)
+
+var pageMarker = regexp.MustCompile(`(?m)^\s*=+\s*Page\s+(\d+)\s*=+\s*$`)
+
+const imageTranscriptionPrompt = `Transcribe ALL text in this image (a candidate's CV, résumé, portfolio or project document). Return ONLY the raw text without any additional comments, formatting, or explanations.
+
+Keep the reading order of the document: headings, then the content under each heading. For multi-column layouts, transcribe each column completely before the next one.
+Return the text exactly as it appears in the image.`
+
+// imageExtractor mengekstrak teks dari upload gambar (PNG / JPEG / WebP) dengan mengirim
+// gambarnya sebagai inline_data, sama seperti fallback Gemini untuk PDF
+func (g *GeminiClient) imageExtractor(mimeType string) TextExtractor {
+	return func(data []byte) (*ExtractionResult, error) {
+		text, modelName, err := g.generateTextFromInlineData(context.Background(), imageTranscriptionPrompt,
+			[]inlineData{{MIMEType: mimeType, Data: data}}, "image extraction")
+		if err != nil {
+			return &ExtractionResult{Method: visionMethod, Model: modelName}, fmt.Errorf("failed to extract text from image: %w", err)
+		}
+
+		result := newExtractionResult(visionMethod, normalizeText(text))
+		result.Model = modelName
+		result.PageCount = 1
+		result.ImagePages = []int{1}
+		return result, nil
+	}
+}
+
+// extractScannedPages membaca halaman PDF yang tidak punya text layer (hasil scan) dari
+// gambar halamannya. Halaman dikirim per batch; hasilnya dipetakan kembali per nomor halaman.
+func (g *GeminiClient) extractScannedPages(reader *model.PdfReader, pageNums []int) (map[int]string, string, []string) {
+	var warnings []string
+	var pages []int
+	var images []inlineData
+	for _, num := range pageNums {
+		page, err := reader.GetPage(num)
+		if err != nil {
+			continue
+		}
+		img, err := pdfPageImage(page)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("page %d: failed to read page image: %v", num, err))
+			continue
+		}
+		if img == nil {
+			continue // halaman benar-benar kosong, bukan scan
+		}
+		pages = append(pages, num)
+		images = append(images, *img)
+	}
+
+	texts := map[int]string{}
+	usedModel := ""
+	for start := 0; start < len(pages); {
+		end, size := start, 0
+		for end < len(pages) && end-start < imagePagesPerRequest {
+			if end > start && size+len(images[end].Data) > maxImageBatchBytes {
+				break
+			}
+			size += len(images[end].Data)
+			end++
+		}
+
+		batch := pages[start:end]
+		text, modelName, err := g.generateTextFromInlineData(context.Background(), scannedPagesPrompt(batch), images[start:end], "scanned page extraction")
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("pages %v: image extraction failed: %v", batch, err))
+		} else {
+			usedModel = modelName
+			for num, pageText := range splitPageTranscript(text, batch) {
+				texts[num] = normalizeText(pageText)
+			}
+		}
+		start = end
+	}
+	return texts, usedModel, warnings
+}
+
+// pdfPageImage mengambil gambar terbesar di halaman sebagai JPEG, atau nil kalau halaman
+// tidak berisi gambar yang cukup besar untuk dianggap scan satu halaman
+func pdfPageImage(page *model.PdfPage) (*inlineData, error) {
+	ex, err := extractor.New(page)
+	if err != nil {
+		return nil, err
+	}
+	pageImages, err := ex.ExtractPageImages(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var largest *extractor.ImageMark
+	for i := range pageImages.Images {
+		m := &pageImages.Images[i]
+		if largest == nil || m.Width*m.Height > largest.Width*largest.Height {
+			largest = m
+		}
+	}
+	if largest == nil || largest.Image == nil {
+		return nil, nil
+	}
+
+	if mediaBox, err := page.GetMediaBox(); err == nil && mediaBox != nil {
+		area := mediaBox.Width() * mediaBox.Height()
+		if area > 0 && largest.Width*largest.Height/area < minPageImageCoverage {
+			return nil, nil
+		}
+	}
+
+	goImg, err := largest.Image.ToGoImage()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, goImg, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return &inlineData{MIMEType: MIMEJPEG, Data: buf.Bytes()}, nil
+}
+
+func scannedPagesPrompt(pages []int) string {
+	nums := make([]string, len(pages))
+	for i, p := range pages {
+		nums[i] = strconv.Itoa(p)
+	}
+	return fmt.Sprintf(`The following %d images are scanned pages %s of a candidate's document (CV, résumé or project report), in this order.
+Transcribe ALL text on each page exactly as it appears. Return ONLY the transcribed text without any additional comments, formatting, or explanations.
+
+Start the text of every page with a line "=== Page N ===" where N is the page number given above.
+For multi-column layouts, transcribe each column completely before the next one.`, len(pages), strings.Join(nums, ", "))
+}
+
+// splitPageTranscript memecah jawaban model berdasarkan marker "=== Page N ===". Kalau
+// batch hanya satu halaman dan model tidak menulis marker, seluruh jawaban dipakai.
+func splitPageTranscript(text string, batch []int) map[int]string {
+	expected := map[int]bool{}
+	for _, p := range batch {
+		expected[p] = true
+	}
+
+	out := map[int]string{}
+	locs := pageMarker.FindAllStringSubmatchIndex(text, -1)
+	if len(locs) == 0 {
+		if len(batch) == 1 {
+			out[batch[0]] = strings.TrimSpace(text)
+		}
+		return out
+	}
+
+	for i, loc := range locs {
+		num, _ := strconv.Atoi(text[loc[2]:loc[3]])
+		if !expected[num] {
+			continue
+		}
+		end := len(text)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+		if pageText := strings.TrimSpace(text[loc[1]:end]); pageText != "" {
+			out[num] = pageText
+		}
+	}
+	return out
+}
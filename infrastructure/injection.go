@@ -0,0 +1,73 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"cv-evaluator/domain"
+)
+
+// DetectCandidateInjection mencari instruksi ke model di teks CV dan project sebuah upload,
+// ditambah teks tersembunyi (putih / sangat kecil / di luar halaman) hasil ekstraksi PDF
+func DetectCandidateInjection(db *gorm.DB, upload domain.Upload) ([]domain.InjectionSpan, error) {
+	spans := domain.DetectInjection(domain.InjectionSourceCV, upload.CVText)
+	spans = append(spans, domain.DetectInjection(domain.InjectionSourceProject, upload.ProjectText)...)
+
+	var metadata []domain.ExtractionMetadata
+	if err := db.Select("id", "kind", "hidden_text").Where("upload_id = ?", upload.ID).Order("id").Find(&metadata).Error; err != nil {
+		return nil, fmt.Errorf("failed to load extraction metadata for upload %d: %w", upload.ID, err)
+	}
+	// Ekstraksi terbaru per dokumen yang dipakai
+	latest := map[string]domain.ExtractionMetadata{}
+	for _, m := range metadata {
+		latest[m.Kind] = m
+	}
+	for _, kind := range []string{domain.InjectionSourceCV, domain.InjectionSourceProject} {
+		var hidden []domain.HiddenTextSpan
+		if raw := latest[kind].HiddenText; raw != "" {
+			_ = json.Unmarshal([]byte(raw), &hidden)
+		}
+		spans = append(spans, domain.HiddenTextInjections(kind, hidden)...)
+	}
+	return spans, nil
+}
+
+// ModelReportedInjections: kutipan "suspicious_instructions" yang dilaporkan model di hasil evaluasi
+func ModelReportedInjections(result map[string]interface{}) []domain.InjectionSpan {
+	items, _ := result["suspicious_instructions"].([]interface{})
+	var spans []domain.InjectionSpan
+	for _, item := range items {
+		text, _ := item.(string)
+		if text = strings.TrimSpace(text); text == "" {
+			continue
+		}
+		spans = append(spans, domain.InjectionSpan{
+			Source: domain.InjectionSourceModel,
+			Kind:   domain.InjectionInstruction,
+			Rule:   "model_reported",
+			Text:   text,
+			Offset: -1,
+		})
+	}
+	return spans
+}
+
+// FlagInjection menyimpan temuan di evaluasi dan menandainya untuk dicek manual. Tidak
+// melakukan apa-apa kalau spans kosong.
+func FlagInjection(db *gorm.DB, evaluationID uint, spans []domain.InjectionSpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	spansJSON, _ := json.Marshal(spans)
+	raw := string(spansJSON)
+	if err := db.Model(&domain.Evaluation{}).Where("id = ?", evaluationID).Updates(map[string]interface{}{
+		"needs_review":    true,
+		"injection_spans": &raw,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to flag evaluation %d for review: %w", evaluationID, err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"cv-evaluator/domain"
+)
+
+// LoadKnockoutFacts mengumpulkan teks CV dan hasil parsing CV terstruktur sebuah upload
+// untuk dicek oleh knockout rule
+func LoadKnockoutFacts(db *gorm.DB, upload domain.Upload) (domain.KnockoutFacts, error) {
+	facts := domain.KnockoutFacts{CVText: upload.CVText}
+
+	var timeline domain.ExperienceTimeline
+	err := db.Where("upload_id = ?", upload.ID).Limit(1).Find(&timeline).Error
+	if err != nil {
+		return facts, fmt.Errorf("failed to load experience timeline: %w", err)
+	}
+	if timeline.ID != 0 {
+		total := timeline.At(time.Now()).TotalMonths
+		facts.TotalMonths = &total
+	}
+
+	plucks := []struct {
+		model  interface{}
+		column string
+		dst    *[]string
+	}{
+		{&domain.CVSkill{}, "normalized_name", &facts.Skills},
+		{&domain.CVCertification{}, "name", &facts.Certifications},
+		{&domain.CVLanguage{}, "language", &facts.Languages},
+	}
+	for _, p := range plucks {
+		if err := db.Model(p.model).Where("upload_id = ?", upload.ID).Order("id").Pluck(p.column, p.dst).Error; err != nil {
+			return facts, fmt.Errorf("failed to load cv %s: %w", p.column, err)
+		}
+	}
+	return facts, nil
+}
+
+// CheckKnockoutRules menjalankan knockout rule aktif job terhadap upload. failed nil berarti
+// lolos semua rule (atau job tidak punya rule).
+func CheckKnockoutRules(db *gorm.DB, jobID uint, upload domain.Upload) ([]domain.KnockoutCheck, *domain.KnockoutCheck, error) {
+	var rules []domain.KnockoutRule
+	if err := db.Where("job_id = ? AND active = ?", jobID, true).Order("id").Find(&rules).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to load knockout rules: %w", err)
+	}
+	if len(rules) == 0 {
+		return nil, nil, nil
+	}
+
+	facts, err := LoadKnockoutFacts(db, upload)
+	if err != nil {
+		return nil, nil, err
+	}
+	taxonomy, err := LoadSkillTaxonomy(db)
+	if err != nil {
+		return nil, nil, err
+	}
+	checks, failed := domain.EvaluateKnockout(rules, facts, taxonomy)
+	return checks, failed, nil
+}
+
+// ApplyKnockoutRules dipanggil worker sebelum evaluasi LLM: hasil pengecekan disimpan di
+// evaluasi, dan kalau ada rule yang gagal status evaluasi langsung rejected_by_rule. Rule
+// yang hasilnya unknown tidak menolak kandidat, tapi evaluasinya ditandai needs_review.
+// Mengembalikan true kalau evaluasi sudah selesai (ditolak) dan LLM tidak perlu dipanggil.
+func ApplyKnockoutRules(db *gorm.DB, evaluationID, jobID uint, upload domain.Upload) (bool, error) {
+	checks, failed, err := CheckKnockoutRules(db, jobID, upload)
+	if err != nil || checks == nil {
+		return false, err
+	}
+
+	checksJSON, _ := json.Marshal(checks)
+	result := string(checksJSON)
+	updates := map[string]interface{}{"knockout_result": &result}
+	if failed != nil {
+		updates["status"] = "rejected_by_rule"
+		updates["rejected_rule_id"] = failed.RuleID
+		updates["overall_summary"] = fmt.Sprintf("Rejected by knockout rule %q: %s", failed.Name, failed.Detail)
+	} else if unknown := domain.UnknownKnockoutRules(checks); len(unknown) > 0 {
+		log.Printf("⚠️ Evaluation %d flagged for review: knockout rule %q could not be checked (%s)", evaluationID, unknown[0].Name, unknown[0].Detail)
+		updates["needs_review"] = true
+	}
+	if err := db.Model(&domain.Evaluation{}).Where("id = ?", evaluationID).Updates(updates).Error; err != nil {
+		return false, fmt.Errorf("failed to save knockout result: %w", err)
+	}
+	return failed != nil, nil
+}
@@ -0,0 +1,151 @@
+package infrastructure
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"cv-evaluator/domain"
+)
+
+// ExtractedLink adalah URL yang ditemukan di dokumen, dari annotation PDF atau dari teks
+type ExtractedLink struct {
+	URL    string `json:"url"`
+	Type   string `json:"type"`
+	Source string `json:"source"`
+	Page   int    `json:"page,omitempty"`
+}
+
+// DocumentInfo adalah metadata yang disimpan di dalam file (PDF Info dictionary)
+type DocumentInfo struct {
+	Title      string     `json:"title,omitempty"`
+	Author     string     `json:"author,omitempty"`
+	Creator    string     `json:"creator,omitempty"`
+	Producer   string     `json:"producer,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	ModifiedAt *time.Time `json:"modified_at,omitempty"`
+}
+
+var (
+	// URL dengan scheme, "www.", atau host profil yang sering ditulis tanpa scheme
+	textURLPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.|(?:github\.com|gitlab\.com|linkedin\.com|stackoverflow\.com)/)[^\s<>"'` + "`" + `{}|\\^\[\]]+`)
+	emailPattern   = regexp.MustCompile(`(?i)\b[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}\b`)
+)
+
+// Maksimal link yang disimpan per dokumen, supaya dokumen penuh URL tidak membanjiri DB
+const maxLinksPerDocument = 50
+
+// Panjang maksimal URL (kolom document_links.url). URL yang lebih panjang dibuang, bukan
+// dipotong: URL terpotong tidak berguna dan insert-nya gagal di MySQL strict mode.
+const maxLinkURLLength = 2048
+
+// normalizeLink merapikan URL: scheme ditambahkan kalau tidak ada, tanda baca di akhir
+// kalimat dibuang. Mengembalikan "" kalau bukan URL http(s) / mailto yang valid.
+func normalizeLink(raw string) string {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimRight(raw, ".,;:!?)'\"")
+	if raw == "" {
+		return ""
+	}
+
+	lower := strings.ToLower(raw)
+	if strings.HasPrefix(lower, "mailto:") {
+		addr := strings.TrimSpace(raw[len("mailto:"):])
+		if i := strings.IndexByte(addr, '?'); i >= 0 {
+			addr = addr[:i]
+		}
+		if !emailPattern.MatchString(addr) {
+			return ""
+		}
+		return "mailto:" + strings.ToLower(addr)
+	}
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+		if strings.Contains(lower, "://") {
+			// javascript:, file://, dll. tidak disimpan
+			return ""
+		}
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || !strings.Contains(u.Host, ".") {
+		return ""
+	}
+	u.Host = strings.ToLower(u.Host)
+	return u.String()
+}
+
+// linkKey dipakai untuk dedupe: "https://github.com/x/" dan "http://www.github.com/x" sama
+func linkKey(link string) string {
+	k := strings.ToLower(link)
+	k = strings.TrimPrefix(k, "https://")
+	k = strings.TrimPrefix(k, "http://")
+	k = strings.TrimPrefix(k, "www.")
+	return strings.TrimRight(k, "/")
+}
+
+// addLink menambahkan link kalau valid dan belum ada
+func (r *ExtractionResult) addLink(raw, source string, page int) {
+	if len(r.Links) >= maxLinksPerDocument {
+		return
+	}
+	link := normalizeLink(raw)
+	if link == "" || len(link) > maxLinkURLLength {
+		return
+	}
+	key := linkKey(link)
+	for _, l := range r.Links {
+		if linkKey(l.URL) == key {
+			return
+		}
+	}
+	r.Links = append(r.Links, ExtractedLink{
+		URL:    link,
+		Type:   domain.ClassifyLink(link),
+		Source: source,
+		Page:   page,
+	})
+}
+
+// collectTextLinks mencari URL dan alamat email yang tertulis di teks hasil ekstraksi.
+// Link dari annotation PDF sudah ditambahkan lebih dulu, jadi tidak terduplikasi.
+func (r *ExtractionResult) collectTextLinks() {
+	if r.skipTextLinks {
+		return
+	}
+	for _, m := range textURLPattern.FindAllString(r.Text, -1) {
+		r.addLink(m, domain.LinkSourceText, 0)
+	}
+	for _, m := range emailPattern.FindAllString(r.Text, -1) {
+		r.addLink("mailto:"+m, domain.LinkSourceText, 0)
+	}
+}
+
+// LinkRecords mengubah link hasil ekstraksi jadi record yang disimpan per upload
+func (r *ExtractionResult) LinkRecords(kind string) []domain.DocumentLink {
+	records := make([]domain.DocumentLink, 0, len(r.Links))
+	for _, l := range r.Links {
+		records = append(records, domain.DocumentLink{
+			Kind:   kind,
+			URL:    l.URL,
+			Type:   l.Type,
+			Source: l.Source,
+			Page:   l.Page,
+		})
+	}
+	return records
+}
+
+// formatLinks menyusun daftar link untuk prompt, satu per baris: "- github: https://..."
+func formatLinks(links []domain.DocumentLink) string {
+	if len(links) == 0 {
+		return "(none)"
+	}
+	var b strings.Builder
+	for _, l := range links {
+		fmt.Fprintf(&b, "- %s: %s\n", l.Type, l.URL)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,128 @@
+package infrastructure
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	mdHeading       = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.*?)\s*#*\s*$`)
+	mdSetextLine    = regexp.MustCompile(`^\s{0,3}(=+|-+)\s*$`)
+	mdBullet        = regexp.MustCompile(`^(\s*)[-*+]\s+(\[[ xX]\]\s+)?`)
+	mdBlockquote    = regexp.MustCompile(`^\s{0,3}>\s?`)
+	mdHorizontal    = regexp.MustCompile(`^\s{0,3}([-*_])(\s*[-*_]){2,}\s*$`)
+	mdTableDivider  = regexp.MustCompile(`^\s*\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?\s*$`)
+	mdImage         = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink          = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	mdRefDefinition = regexp.MustCompile(`^\s{0,3}\[[^\]]+\]:\s+\S+`)
+	mdAutolink      = regexp.MustCompile(`<(https?://[^>]+)>`)
+	mdInlineCode    = regexp.MustCompile("`+([^`]+)`+")
+	mdBold          = regexp.MustCompile(`(\*\*|__)(\S(?:.*?\S)?)(\*\*|__)`)
+	mdItalic        = regexp.MustCompile(`(^|[^\w*])[*_](\S(?:[^*_]*?\S)?)[*_]([^\w*]|$)`)
+	mdStrike        = regexp.MustCompile(`~~(.+?)~~`)
+	mdHTMLTag       = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+)
+
+// extractTextFromMarkdown strips Markdown syntax but keeps the structure: headings
+// and paragraphs stay on their own lines, lists become "- " items, code blocks are
+// kept as-is and links are rendered as "text (url)"
+func extractTextFromMarkdown(data []byte) (string, error) {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	var out []string
+	inFence := false
+	fence := ""
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		// Code fence: isi code block disimpan apa adanya, fence-nya dibuang
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			marker := trimmed[:3]
+			if !inFence {
+				inFence, fence = true, marker
+				continue
+			}
+			if marker == fence {
+				inFence = false
+				continue
+			}
+		}
+		if inFence {
+			out = append(out, line)
+			continue
+		}
+
+		// Baris pemisah header tabel "|---|---|" tidak punya isi
+		if strings.Contains(line, "|") && mdTableDivider.MatchString(line) {
+			continue
+		}
+
+		if mdHorizontal.MatchString(line) || mdRefDefinition.MatchString(line) {
+			out = append(out, "")
+			continue
+		}
+
+		// Setext heading: baris "===" / "---" di bawah teks
+		if mdSetextLine.MatchString(line) && i > 0 && strings.TrimSpace(lines[i-1]) != "" {
+			out = append(out, "")
+			continue
+		}
+
+		for mdBlockquote.MatchString(line) {
+			line = mdBlockquote.ReplaceAllString(line, "")
+		}
+
+		if m := mdHeading.FindStringSubmatch(line); m != nil {
+			out = append(out, "", stripInlineMarkdown(m[1]), "")
+			continue
+		}
+
+		if m := mdBullet.FindStringSubmatch(line); m != nil {
+			indent := len(strings.ReplaceAll(m[1], "\t", "  ")) / 2
+			item := strings.TrimSpace(line[len(m[0]):])
+			out = append(out, strings.Repeat("  ", indent)+"- "+stripInlineMarkdown(item))
+			continue
+		}
+
+		// Baris tabel: "| a | b |" → "a | b"
+		if strings.HasPrefix(trimmed, "|") && strings.HasSuffix(trimmed, "|") && len(trimmed) > 1 {
+			cells := strings.Split(strings.Trim(trimmed, "|"), "|")
+			for j := range cells {
+				cells[j] = stripInlineMarkdown(strings.TrimSpace(cells[j]))
+			}
+			out = append(out, strings.Join(cells, " | "))
+			continue
+		}
+
+		out = append(out, stripInlineMarkdown(strings.TrimRight(line, " \t")))
+	}
+
+	text := collapseBlankLines(out)
+	if text == "" {
+		return "", fmt.Errorf("no text found in Markdown")
+	}
+
+	fmt.Printf("Successfully extracted text from Markdown (%d characters)\n", len(text))
+	return text, nil
+}
+
+func stripInlineMarkdown(s string) string {
+	s = mdImage.ReplaceAllString(s, "$1")
+	s = mdLink.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mdLink.FindStringSubmatch(m)
+		text, url := sub[1], sub[2]
+		if text == url || strings.HasPrefix(url, "#") {
+			return text
+		}
+		return text + " (" + url + ")"
+	})
+	s = mdAutolink.ReplaceAllString(s, "$1")
+	s = mdInlineCode.ReplaceAllString(s, "$1")
+	s = mdBold.ReplaceAllString(s, "$2")
+	s = mdItalic.ReplaceAllString(s, "$1$2$3")
+	s = mdStrike.ReplaceAllString(s, "$1")
+	s = mdHTMLTag.ReplaceAllString(s, "")
+	return s
+}
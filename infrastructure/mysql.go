@@ -24,18 +24,67 @@ func NewMySQLConnection() *gorm.DB {
 	}
 
 	// Auto migrate schema
-	err = db.AutoMigrate(&domain.Job{}, &domain.Upload{}, &domain.Evaluation{})
+	err = db.AutoMigrate(
+		&domain.Job{},
+		&domain.Candidate{},
+		&domain.UploadBatch{},
+		&domain.Upload{},
+		&domain.UploadBatchItem{},
+		&domain.StoredFile{},
+		&domain.ExtractionMetadata{},
+		&domain.DocumentLink{},
+		&domain.KnockoutRule{},
+		&domain.RedactionMapping{},
+		&domain.PromptTemplate{},
+		&domain.Skill{},
+		&domain.SkillAlias{},
+		&domain.CVProfile{},
+		&domain.CVPosition{},
+		&domain.CVEducation{},
+		&domain.CVSkill{},
+		&domain.CVCertification{},
+		&domain.CVLanguage{},
+		&domain.ExperienceTimeline{},
+		&domain.SkillExperience{},
+		&domain.Evaluation{},
+		&domain.Tournament{},
+		&domain.PairwiseComparison{},
+	)
 	if err != nil {
 		log.Fatalf("failed to migrate database: %v", err)
 	}
 
+	// Pindahkan data kandidat lama dari uploads ke tabel candidates
+	backfillUploadCandidates(db)
+
+	// Evaluasi lama belum punya completed_at
+	backfillEvaluationCompletedAt(db)
+
 	// Seed initial jobs
 	seedJobs(db)
 
+	// Seed taxonomy skill bawaan
+	seedSkillTaxonomy(db)
+
+	// Seed template prompt evaluasi bawaan
+	seedPromptTemplates(db)
+
 	fmt.Println("✅ Connected to MySQL and migrated schema")
 	return db
 }
 
+// backfillEvaluationCompletedAt mengisi completed_at evaluasi completed yang dibuat sebelum
+// kolom itu ada. updated_at hanya bisa dipakai kalau evaluasi belum pernah di-review (review
+// ikut mengubah updated_at); selain itu created_at jadi perkiraan terbaik.
+func backfillEvaluationCompletedAt(db *gorm.DB) {
+	err := db.Model(&domain.Evaluation{}).
+		Where("status = ? AND completed_at IS NULL", "completed").
+		Update("completed_at", gorm.Expr("CASE WHEN reviewed_at IS NULL THEN updated_at ELSE created_at END")).Error
+	if err != nil {
+		log.Printf("❌ Failed to backfill evaluation completed_at: %v", err)
+	}
+}
+
 func seedJobs(db *gorm.DB) {
 	var count int64
 	if err := db.Model(&domain.Job{}).Count(&count).Error; err != nil {
@@ -0,0 +1,189 @@
+package infrastructure
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// extractTextFromODT extracts text from an OpenDocument text file (content.xml),
+// preserving paragraphs, headings, list items and table rows
+func extractTextFromODT(data []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("failed to open ODT: %w", err)
+	}
+
+	var content *zip.File
+	for _, f := range zr.File {
+		if f.Name == "content.xml" {
+			content = f
+			break
+		}
+	}
+	if content == nil {
+		return "", fmt.Errorf("invalid ODT: content.xml not found")
+	}
+	if content.UncompressedSize64 > maxDOCXPartSize {
+		return "", fmt.Errorf("ODT content is too large")
+	}
+
+	rc, err := content.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open content.xml: %w", err)
+	}
+	defer rc.Close()
+
+	text, err := parseODFText(io.LimitReader(rc, maxDOCXPartSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse content.xml: %w", err)
+	}
+	if text == "" {
+		return "", fmt.Errorf("no text found in ODT")
+	}
+
+	fmt.Printf("Successfully extracted text from ODT (%d characters)\n", len(text))
+	return text, nil
+}
+
+// parseODFText: satu baris per text:p / text:h, list item diberi "- " dengan indentasi
+// sesuai kedalaman list, dan baris tabel ditulis sebagai "a | b | c"
+func parseODFText(r io.Reader) (string, error) {
+	dec := xml.NewDecoder(r)
+
+	var (
+		out       []string
+		para      strings.Builder
+		paraDepth int // text:p / text:h bisa nested (mis. di dalam note)
+		listDepth int
+		inItem    bool
+		inNote    int
+		tables    []*docxTable
+	)
+
+	emit := func(line string) {
+		if len(tables) > 0 {
+			t := tables[len(tables)-1]
+			if len(t.cells) > 0 {
+				cell := t.cells[len(t.cells)-1]
+				if line != "" {
+					cell.paragraphs = append(cell.paragraphs, line)
+				}
+				return
+			}
+		}
+		out = append(out, line)
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p", "h":
+				if paraDepth == 0 {
+					para.Reset()
+				}
+				paraDepth++
+			case "list":
+				listDepth++
+			case "list-item":
+				inItem = true
+			case "note":
+				// Footnote/endnote di-skip supaya tidak memotong kalimat utama
+				inNote++
+			case "tab":
+				para.WriteString("\t")
+			case "line-break":
+				para.WriteString("\n")
+			case "s":
+				n := 1
+				if v := xmlAttr(t, "c"); v != "" {
+					if c, err := strconv.Atoi(v); err == nil && c > 0 {
+						n = c
+					}
+				}
+				para.WriteString(strings.Repeat(" ", n))
+			case "table":
+				tables = append(tables, &docxTable{})
+			case "table-cell":
+				if len(tables) > 0 {
+					tbl := tables[len(tables)-1]
+					tbl.cells = append(tbl.cells, &docxCell{})
+				}
+			}
+
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "p", "h":
+				if paraDepth == 0 {
+					continue
+				}
+				paraDepth--
+				if paraDepth > 0 {
+					continue
+				}
+				line := strings.TrimRight(para.String(), " \t")
+				if inItem && listDepth > 0 && strings.TrimSpace(line) != "" {
+					line = strings.Repeat("  ", listDepth-1) + "- " + strings.TrimSpace(line)
+					// Paragraf berikutnya dalam item yang sama tidak diberi bullet lagi
+					inItem = false
+				}
+				emit(line)
+			case "list":
+				if listDepth > 0 {
+					listDepth--
+				}
+			case "list-item":
+				inItem = false
+			case "note":
+				if inNote > 0 {
+					inNote--
+				}
+			case "table-cell":
+				if len(tables) > 0 {
+					tbl := tables[len(tables)-1]
+					if n := len(tbl.cells); n > 0 {
+						cell := tbl.cells[n-1]
+						tbl.cells = tbl.cells[:n-1]
+						tbl.row = append(tbl.row, strings.Join(cell.paragraphs, " "))
+					}
+				}
+			case "table-row":
+				if len(tables) > 0 {
+					tbl := tables[len(tables)-1]
+					if strings.TrimSpace(strings.Join(tbl.row, "")) != "" {
+						line := strings.Join(tbl.row, " | ")
+						tables = tables[:len(tables)-1]
+						emit(line)
+						tables = append(tables, tbl)
+					}
+					tbl.row = nil
+				}
+			case "table":
+				if len(tables) > 0 {
+					tables = tables[:len(tables)-1]
+					emit("")
+				}
+			}
+
+		case xml.CharData:
+			if paraDepth > 0 && inNote == 0 {
+				para.Write(t)
+			}
+		}
+	}
+
+	return collapseBlankLines(out), nil
+}
@@ -0,0 +1,84 @@
+package infrastructure
+
+import (
+	"strings"
+
+	"github.com/unidoc/unipdf/v3/extractor"
+	"github.com/unidoc/unipdf/v3/model"
+
+	"cv-evaluator/domain"
+)
+
+const (
+	// Komponen warna di atas ini (0-1) dianggap putih
+	whiteTextLevel = 0.94
+	// Glyph yang lebih pendek dari ini (point) tidak terbaca di layar maupun hasil print
+	tinyTextHeight = 2.0
+)
+
+// extractPDFHiddenText mencari teks yang ada di text layer tapi tidak terlihat pembaca:
+// teks putih, teks sangat kecil dan teks di luar halaman. Teks seperti ini dibaca model
+// tapi tidak oleh reviewer, jadi dicatat sebagai kandidat prompt injection.
+func extractPDFHiddenText(page *model.PdfPage, pageNum int) []domain.HiddenTextSpan {
+	ex, err := extractor.New(page)
+	if err != nil {
+		return nil
+	}
+	pageText, _, _, err := ex.ExtractPageText()
+	if err != nil {
+		return nil
+	}
+	mediaBox, err := page.GetMediaBox()
+	if err != nil || mediaBox == nil {
+		return nil
+	}
+
+	var spans []domain.HiddenTextSpan
+	var run strings.Builder
+	runReason := ""
+	flush := func() {
+		if text := strings.TrimSpace(run.String()); runReason != "" && domain.KeepHiddenText(runReason, text) {
+			spans = append(spans, domain.HiddenTextSpan{Page: pageNum, Reason: runReason, Text: text})
+		}
+		run.Reset()
+		runReason = ""
+	}
+
+	for _, m := range pageText.Marks().Elements() {
+		// Spasi / line break sisipan extractor ikut run yang sedang berjalan
+		if m.Meta || strings.TrimSpace(m.Text) == "" {
+			if runReason != "" {
+				run.WriteString(m.Text)
+			}
+			continue
+		}
+		reason := hiddenReason(m, *mediaBox)
+		if reason != runReason {
+			flush()
+		}
+		if reason != "" {
+			runReason = reason
+			run.WriteString(m.Text)
+		}
+	}
+	flush()
+	return spans
+}
+
+func hiddenReason(m extractor.TextMark, mediaBox model.PdfRectangle) string {
+	b := m.BBox
+	if b.Urx < mediaBox.Llx || b.Llx > mediaBox.Urx || b.Ury < mediaBox.Lly || b.Lly > mediaBox.Ury {
+		return domain.HiddenOffPage
+	}
+	if h := b.Ury - b.Lly; h > 0 && h < tinyTextHeight {
+		return domain.HiddenTinyText
+	}
+	if m.FillColor != nil {
+		r, g, bl, a := m.FillColor.RGBA()
+		level := whiteTextLevel * 0xffff
+		if a > 0 && float64(r) >= level && float64(g) >= level && float64(bl) >= level {
+			return domain.HiddenWhiteText
+		}
+	}
+	return ""
+}
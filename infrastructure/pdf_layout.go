@@ -0,0 +1,172 @@
+package infrastructure
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/unidoc/unipdf/v3/extractor"
+	"github.com/unidoc/unipdf/v3/model"
+)
+
+const (
+	// Gutter dicari di area tengah halaman saja (sidebar résumé biasanya 25-45% lebar)
+	gutterSearchFrom = 0.2
+	gutterSearchTo   = 0.8
+	// Lebar minimal area kosong vertikal yang dianggap gutter (dalam point)
+	minGutterWidth = 8.0
+	// Maksimal porsi karakter yang boleh "menyeberang" gutter di bawah header
+	maxCrossingRatio = 0.02
+	// Setiap kolom minimal harus berisi porsi karakter ini
+	minColumnShare = 0.15
+	// Baris yang menyeberang gutter di bagian atas halaman dianggap header full-width
+	headerZone = 0.3
+)
+
+// extractPDFPageText extracts the text of one page. When the page has a two-column
+// layout (detected from text positions), each column is extracted separately so the
+// lines of the sidebar and the main column are not interleaved.
+func extractPDFPageText(page *model.PdfPage) (string, bool, error) {
+	ex, err := extractor.New(page)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create extractor: %w", err)
+	}
+
+	pageText, _, _, err := ex.ExtractPageText()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to extract text: %w", err)
+	}
+
+	mediaBox, err := page.GetMediaBox()
+	if err != nil || mediaBox == nil {
+		return pageText.Text(), false, nil
+	}
+
+	layout, ok := detectColumns(pageText.Marks().Elements(), *mediaBox)
+	if !ok {
+		return pageText.Text(), false, nil
+	}
+
+	areas := []model.PdfRectangle{}
+	if layout.headerBottom < mediaBox.Ury {
+		areas = append(areas, model.PdfRectangle{Llx: mediaBox.Llx, Lly: layout.headerBottom, Urx: mediaBox.Urx, Ury: mediaBox.Ury})
+	}
+	areas = append(areas,
+		model.PdfRectangle{Llx: mediaBox.Llx, Lly: mediaBox.Lly, Urx: layout.gutter, Ury: layout.headerBottom},
+		model.PdfRectangle{Llx: layout.gutter, Lly: mediaBox.Lly, Urx: mediaBox.Urx, Ury: layout.headerBottom},
+	)
+
+	var parts []string
+	for _, area := range areas {
+		// ApplyArea mengubah PageText, jadi setiap area butuh hasil ekstraksi sendiri
+		areaText, _, _, err := ex.ExtractPageText()
+		if err != nil {
+			return pageText.Text(), false, nil
+		}
+		areaText.ApplyArea(area)
+		if text := strings.TrimSpace(areaText.Text()); text != "" {
+			parts = append(parts, text)
+		}
+	}
+
+	return strings.Join(parts, "\n\n"), true, nil
+}
+
+type columnLayout struct {
+	gutter       float64 // posisi x pemisah kolom
+	headerBottom float64 // y di bawah header full-width (sama dengan Ury kalau tidak ada header)
+}
+
+// detectColumns mencari gutter vertikal: posisi x di tengah halaman yang (hampir) tidak
+// dilewati teks, dengan cukup banyak teks di kiri dan kanannya
+func detectColumns(marks []extractor.TextMark, mediaBox model.PdfRectangle) (columnLayout, bool) {
+	width := mediaBox.Urx - mediaBox.Llx
+	height := mediaBox.Ury - mediaBox.Lly
+	if width <= 0 || height <= 0 {
+		return columnLayout{}, false
+	}
+
+	var glyphs []extractor.TextMark
+	for _, m := range marks {
+		if m.Meta || strings.TrimSpace(m.Text) == "" || m.BBox.Urx <= m.BBox.Llx {
+			continue
+		}
+		glyphs = append(glyphs, m)
+	}
+	if len(glyphs) < 200 {
+		// Terlalu sedikit teks untuk menyimpulkan layout
+		return columnLayout{}, false
+	}
+
+	// Coverage per 1pt: berapa glyph yang menutupi posisi x tersebut
+	bins := int(math.Ceil(width))
+	coverage := make([]int, bins+1)
+	for _, g := range glyphs {
+		from := int(math.Max(0, g.BBox.Llx-mediaBox.Llx))
+		to := int(math.Min(float64(bins), g.BBox.Urx-mediaBox.Llx))
+		for x := from; x <= to; x++ {
+			coverage[x]++
+		}
+	}
+
+	// Cari run terpanjang dengan coverage minimum di area tengah
+	lo, hi := int(float64(bins)*gutterSearchFrom), int(float64(bins)*gutterSearchTo)
+	threshold := int(float64(len(glyphs)) * maxCrossingRatio)
+	bestStart, bestLen, runStart := -1, 0, -1
+	for x := lo; x <= hi; x++ {
+		if coverage[x] <= threshold {
+			if runStart == -1 {
+				runStart = x
+			}
+			if n := x - runStart + 1; n > bestLen {
+				bestStart, bestLen = runStart, n
+			}
+		} else {
+			runStart = -1
+		}
+	}
+	if bestStart == -1 || float64(bestLen) < minGutterWidth {
+		return columnLayout{}, false
+	}
+	gutter := mediaBox.Llx + float64(bestStart) + float64(bestLen)/2
+
+	// Glyph yang menyeberang gutter di bagian atas → header full-width (nama, kontak)
+	headerBottom := mediaBox.Ury
+	headerLimit := mediaBox.Ury - height*headerZone
+	var crossing []extractor.TextMark
+	for _, g := range glyphs {
+		if g.BBox.Llx < gutter && g.BBox.Urx > gutter {
+			crossing = append(crossing, g)
+		}
+	}
+	sort.Slice(crossing, func(i, j int) bool { return crossing[i].BBox.Lly > crossing[j].BBox.Lly })
+	for _, g := range crossing {
+		if g.BBox.Lly < headerLimit {
+			break
+		}
+		headerBottom = math.Min(headerBottom, g.BBox.Lly-1)
+	}
+
+	// Setiap kolom harus punya porsi teks yang cukup di bawah header
+	left, right, body := 0, 0, 0
+	for _, g := range glyphs {
+		if g.BBox.Ury > headerBottom {
+			continue
+		}
+		body++
+		center := (g.BBox.Llx + g.BBox.Urx) / 2
+		if center < gutter {
+			left++
+		} else {
+			right++
+		}
+	}
+	if body == 0 ||
+		float64(left)/float64(body) < minColumnShare ||
+		float64(right)/float64(body) < minColumnShare {
+		return columnLayout{}, false
+	}
+
+	return columnLayout{gutter: gutter, headerBottom: headerBottom}, true
+}
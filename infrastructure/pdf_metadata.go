@@ -0,0 +1,81 @@
+package infrastructure
+
+import (
+	"strings"
+	"time"
+
+	"github.com/unidoc/unipdf/v3/core"
+	"github.com/unidoc/unipdf/v3/model"
+)
+
+// extractPDFLinks mengambil URL dari link annotation di satu halaman. Link seperti ini
+// (mis. ikon GitHub / LinkedIn yang bisa diklik) tidak muncul di hasil ExtractText.
+func extractPDFLinks(page *model.PdfPage) []string {
+	annotations, err := page.GetAnnotations()
+	if err != nil {
+		return nil
+	}
+
+	var links []string
+	for _, annot := range annotations {
+		link, ok := annot.GetContext().(*model.PdfAnnotationLink)
+		if !ok {
+			continue
+		}
+		action, err := link.GetAction()
+		if err != nil || action == nil {
+			continue // link internal (Dest) ke halaman lain
+		}
+		uri, ok := action.GetContext().(*model.PdfActionURI)
+		if !ok || uri.URI == nil {
+			continue
+		}
+		if s, ok := core.GetString(uri.URI); ok {
+			links = append(links, s.Decoded())
+		}
+	}
+	return links
+}
+
+// extractPDFInfo membaca Info dictionary PDF (title, author, tanggal dibuat, producer)
+func extractPDFInfo(reader *model.PdfReader) *DocumentInfo {
+	info, err := reader.GetPdfInfo()
+	if err != nil || info == nil {
+		return nil
+	}
+
+	doc := &DocumentInfo{
+		Title:    pdfString(info.Title),
+		Author:   pdfString(info.Author),
+		Creator:  pdfString(info.Creator),
+		Producer: pdfString(info.Producer),
+	}
+	if info.CreationDate != nil {
+		t := info.CreationDate.ToGoTime()
+		doc.CreatedAt = zeroTimeToNil(&t)
+	}
+	if info.ModifiedDate != nil {
+		t := info.ModifiedDate.ToGoTime()
+		doc.ModifiedAt = zeroTimeToNil(&t)
+	}
+
+	if *doc == (DocumentInfo{}) {
+		return nil
+	}
+	return doc
+}
+
+func pdfString(s *core.PdfObjectString) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(normalizeText(s.Decoded()))
+}
+
+// zeroTimeToNil: tanggal PDF yang tidak valid di-parse unipdf jadi zero time
+func zeroTimeToNil(t *time.Time) *time.Time {
+	if t == nil || t.IsZero() || t.Year() < 1980 {
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,58 @@
+package infrastructure
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"gorm.io/gorm"
+
+	"cv-evaluator/domain"
+)
+
+// PrescreenScope menentukan upload yang di-ranking. Tanpa UploadIDs dan BatchID, semua
+// upload yang teksnya sudah siap ikut di-ranking.
+type PrescreenScope struct {
+	UploadIDs []uint
+	BatchID   *uint
+}
+
+// LoadPrescreenTopN membaca PRESCREEN_TOP_N: default jumlah upload teratas yang lolos
+// pre-screening ke evaluasi LLM
+func LoadPrescreenTopN() int {
+	if n, err := strconv.Atoi(os.Getenv("PRESCREEN_TOP_N")); err == nil && n > 0 {
+		return n
+	}
+	return 20
+}
+
+// PrescreenUploads menilai CVText setiap upload di scope terhadap deskripsi + kriteria
+// rubric job dengan BM25, tanpa memanggil model. Teks dibaca per batch dan hanya frekuensi
+// term yang disimpan, jadi ribuan upload tetap ringan.
+func PrescreenUploads(db *gorm.DB, job domain.Job, scope PrescreenScope) ([]domain.BM25Score, error) {
+	taxonomy, err := LoadSkillTaxonomy(db)
+	if err != nil {
+		return nil, err
+	}
+	index := domain.NewBM25Index(domain.PrescreenTerms(domain.PrescreenQueryText(job), taxonomy))
+
+	query := db.Model(&domain.Upload{}).Select("id", "cv_text").Where("status = ?", "ready")
+	if len(scope.UploadIDs) > 0 {
+		query = query.Where("id IN ?", scope.UploadIDs)
+	}
+	if scope.BatchID != nil {
+		query = query.Where("batch_id = ?", *scope.BatchID)
+	}
+
+	var uploads []domain.Upload
+	err = query.FindInBatches(&uploads, 200, func(tx *gorm.DB, batch int) error {
+		for _, u := range uploads {
+			index.Add(u.ID, domain.PrescreenTerms(u.CVText, taxonomy))
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load uploads: %w", err)
+	}
+	return index.Rank(), nil
+}
@@ -0,0 +1,189 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"cv-evaluator/domain"
+)
+
+// Model untuk prompt yang hasilnya JSON (evaluasi, perbandingan, parsing CV), dicoba berurutan
+var jsonModels = []string{
+	"gemini-2.0-flash-001",
+	"gemini-2.0-flash",
+	"gemini-2.5-flash",
+	"gemini-2.5-flash-preview-09-2025",
+	"gemini-flash-latest",
+}
+
+// Limit token input per model. Semua memakai tokenizer Gemini, jadi perkiraan jumlah token
+// (domain.EstimateTokens) sama; yang berbeda hanya limit-nya.
+var modelInputTokens = map[string]int{
+	"gemini-2.0-flash-001":             1048576,
+	"gemini-2.0-flash":                 1048576,
+	"gemini-2.5-flash":                 1048576,
+	"gemini-2.5-flash-preview-09-2025": 1048576,
+	"gemini-flash-latest":              1048576,
+}
+
+const (
+	// Limit untuk model yang tidak ada di tabel
+	defaultModelInputTokens = 32768
+	// Cadangan token untuk jawaban model
+	promptOutputReserve = 8192
+	// Perkiraan token instruksi tetap di template prompt (kriteria, aturan, format output)
+	promptInstructionTokens = 1500
+)
+
+func modelInputLimit(model string) int {
+	if n, ok := modelInputTokens[model]; ok {
+		return n
+	}
+	return defaultModelInputTokens
+}
+
+// LoadPromptTokenBudget baca PROMPT_TOKEN_BUDGET: perkiraan maksimal token satu prompt
+// evaluasi / perbandingan (default 32000). Budget juga dibatasi limit model terkecil.
+func LoadPromptTokenBudget() int {
+	if n, err := strconv.Atoi(os.Getenv("PROMPT_TOKEN_BUDGET")); err == nil && n > 0 {
+		return n
+	}
+	return 32000
+}
+
+// contentBudget: token yang tersedia untuk isi job, rubric dan dokumen kandidat setelah
+// dikurangi token instruksi tetap
+func (g *GeminiClient) contentBudget(instructionTokens int) int {
+	budget := g.promptTokens
+	for _, m := range jsonModels {
+		if limit := modelInputLimit(m) - promptOutputReserve; limit < budget {
+			budget = limit
+		}
+	}
+	if instructionTokens <= 0 {
+		instructionTokens = promptInstructionTokens
+	}
+	return budget - instructionTokens
+}
+
+// PromptInput adalah isi prompt evaluasi (satu kandidat) atau perbandingan (dua kandidat)
+type PromptInput struct {
+	Description string
+	Rubric      string
+	Candidates  []CandidateDocuments
+
+	// Perkiraan token instruksi tetap (template, aturan, format output); 0 = promptInstructionTokens
+	InstructionTokens int
+}
+
+// Bobot pembagian budget kalau isi prompt melebihi budget
+const (
+	jobPromptWeight     = 1
+	rubricPromptWeight  = 1
+	cvPromptWeight      = 3
+	projectPromptWeight = 4
+)
+
+// ErrPromptBudget dikembalikan FitPrompt kalau budget token tidak cukup untuk dokumen kandidat
+var ErrPromptBudget = errors.New("prompt token budget too small")
+
+// Link dan fakta pengalaman semua kandidat maksimal 1/maxFixedPromptShare budget, supaya
+// CV / project tetap punya tempat
+const maxFixedPromptShare = 4
+
+// FitPrompt memangkas job, rubric dan dokumen kandidat supaya prompt muat di budget token.
+// Link dan fakta pengalaman dikirim utuh selama muat di jatahnya sendiri (lihat
+// maxFixedPromptShare), kalau tidak dipangkas juga. Dokumen kandidat dipangkas per blok dengan
+// mendahulukan bagian yang paling relevan dengan job dan rubric. Kalau budget tidak cukup
+// untuk menyisakan isi CV / project sama sekali, ErrPromptBudget dikembalikan.
+func (g *GeminiClient) FitPrompt(in PromptInput) (PromptInput, domain.PromptReport, error) {
+	budget := g.contentBudget(in.InstructionTokens)
+	report := domain.PromptReport{BudgetTokens: budget}
+	if budget <= 0 {
+		return in, report, fmt.Errorf("%w: prompt instructions need more than the %d token budget", ErrPromptBudget, g.promptTokens)
+	}
+
+	type part struct {
+		section  string
+		text     *string
+		weight   float64
+		document bool
+	}
+	out := in
+	out.Candidates = append([]CandidateDocuments(nil), in.Candidates...)
+	parts := []part{
+		{"job_description", &out.Description, jobPromptWeight, false},
+		{"rubric", &out.Rubric, rubricPromptWeight, false},
+	}
+	fixed := 0
+	for i := range out.Candidates {
+		suffix := ""
+		if len(out.Candidates) > 1 {
+			suffix = "_" + string(rune('a'+i))
+		}
+		parts = append(parts,
+			part{"cv" + suffix, &out.Candidates[i].CVText, cvPromptWeight, true},
+			part{"project" + suffix, &out.Candidates[i].ProjectText, projectPromptWeight, true},
+		)
+
+		// Fakta pengalaman didahulukan, link memakai sisa jatah kandidat ini
+		share := budget / maxFixedPromptShare / len(out.Candidates)
+		c := &out.Candidates[i]
+		var experience, links domain.PromptSectionReport
+		c.Experience, experience = domain.FitText("experience"+suffix, c.Experience, share/2)
+		c.Links, links = fitLinks("links"+suffix, c.Links, share-experience.Tokens)
+		report.Add(experience)
+		report.Add(links)
+		fixed += experience.Tokens + links.Tokens
+	}
+
+	reqs := make([]domain.BudgetRequest, len(parts))
+	for i, p := range parts {
+		reqs[i] = domain.BudgetRequest{Tokens: domain.EstimateTokens(*p.text), Weight: p.weight}
+	}
+	alloc := domain.AllocateTokenBudget(budget-fixed, reqs)
+
+	query := domain.Tokenize(in.Description + "\n" + in.Rubric)
+	for i, p := range parts {
+		if p.document && reqs[i].Tokens > 0 && alloc[i] <= 0 {
+			return in, report, fmt.Errorf("%w: no tokens left for %s (budget %d)", ErrPromptBudget, p.section, budget)
+		}
+		var section domain.PromptSectionReport
+		if p.document {
+			*p.text, section = domain.FitDocument(p.section, *p.text, alloc[i], query)
+		} else {
+			*p.text, section = domain.FitText(p.section, *p.text, alloc[i])
+		}
+		report.Add(section)
+	}
+	return out, report, nil
+}
+
+// fitLinks menyimpan link sesuai urutan selama daftar yang diformat muat di budget
+func fitLinks(section string, links []domain.DocumentLink, budget int) ([]domain.DocumentLink, domain.PromptSectionReport) {
+	report := domain.PromptSectionReport{Section: section, OriginalTokens: domain.EstimateTokens(formatLinks(links)), BudgetTokens: budget}
+	if report.OriginalTokens <= budget {
+		report.Tokens = report.OriginalTokens
+		return links, report
+	}
+	kept := links
+	for len(kept) > 0 && domain.EstimateTokens(formatLinks(kept)) > budget {
+		kept = kept[:len(kept)-1]
+	}
+	report.Tokens = domain.EstimateTokens(formatLinks(kept))
+	report.Truncated = true
+	report.OmittedParts = len(links) - len(kept)
+	return kept, report
+}
+
+// fitCVForParsing memangkas teks CV untuk prompt parsing (jarang terjadi, CV biasanya pendek)
+func (g *GeminiClient) fitCVForParsing(cvText string) string {
+	text, report := domain.FitText("cv", cvText, g.contentBudget(0))
+	if report.Truncated {
+		log.Printf("✂️ CV text truncated for parsing: ~%d of ~%d tokens kept", report.Tokens, report.OriginalTokens)
+	}
+	return text
+}
@@ -20,11 +20,24 @@ type EvaluationJob struct {
 	ProjectText  string `json:"project_text"`
 }
 
+// Job message untuk perbandingan pairwise dua kandidat (tournament)
+type ComparisonJob struct {
+	ComparisonID uint `json:"comparison_id"`
+	TournamentID uint `json:"tournament_id"`
+}
+
+// Job message untuk ekstraksi teks dari file original sebuah upload
+type ExtractionJob struct {
+	UploadID uint `json:"upload_id"`
+}
+
 // Struct untuk RabbitMQ client
 type RabbitMQ struct {
-	conn    *amqp.Connection
-	channel *amqp.Channel
-	queue   amqp.Queue
+	conn            *amqp.Connection
+	channel         *amqp.Channel
+	queue           amqp.Queue
+	comparisonQueue amqp.Queue
+	extractionQueue amqp.Queue
 }
 
 // Inisialisasi koneksi RabbitMQ
@@ -44,26 +57,47 @@ func NewRabbitMQ() *RabbitMQ {
 		log.Fatalf("failed to open channel: %v", err)
 	}
 
+	q := declareQueue(ch, "evaluation_queue")
+	cq := declareQueue(ch, "comparison_queue")
+	eq := declareQueue(ch, "extraction_queue")
+
+	fmt.Println("✅ Connected to RabbitMQ and declared queues")
+
+	return &RabbitMQ{conn: conn, channel: ch, queue: q, comparisonQueue: cq, extractionQueue: eq}
+}
+
+func declareQueue(ch *amqp.Channel, name string) amqp.Queue {
 	q, err := ch.QueueDeclare(
-		"evaluation_queue", // queue name
-		true,               // durable
-		false,              // delete when unused
-		false,              // exclusive
-		false,              // no-wait
-		nil,                // args
+		name,  // queue name
+		true,  // durable
+		false, // delete when unused
+		false, // exclusive
+		false, // no-wait
+		nil,   // args
 	)
 	if err != nil {
-		log.Fatalf("failed to declare queue: %v", err)
+		log.Fatalf("failed to declare queue %s: %v", name, err)
 	}
-
-	fmt.Println("✅ Connected to RabbitMQ and declared queue")
-
-	return &RabbitMQ{conn: conn, channel: ch, queue: q}
+	return q
 }
 
 // Publish job ke queue
 func (r *RabbitMQ) PublishJob(job EvaluationJob) error {
-	body, err := json.Marshal(job)
+	return r.publish(r.queue.Name, job)
+}
+
+// Publish job perbandingan ke comparison queue
+func (r *RabbitMQ) PublishComparison(job ComparisonJob) error {
+	return r.publish(r.comparisonQueue.Name, job)
+}
+
+// Publish job ekstraksi dokumen ke extraction queue
+func (r *RabbitMQ) PublishExtraction(job ExtractionJob) error {
+	return r.publish(r.extractionQueue.Name, job)
+}
+
+func (r *RabbitMQ) publish(queueName string, v interface{}) error {
+	body, err := json.Marshal(v)
 	if err != nil {
 		return err
 	}
@@ -73,8 +107,8 @@ func (r *RabbitMQ) PublishJob(job EvaluationJob) error {
 
 	return r.channel.PublishWithContext(
 		ctx,
-		"",           // exchange
-		r.queue.Name, // routing key
+		"",        // exchange
+		queueName, // routing key
 		false,
 		false,
 		amqp.Publishing{
@@ -86,18 +120,7 @@ func (r *RabbitMQ) PublishJob(job EvaluationJob) error {
 
 // Consume job dari queue (untuk worker)
 func (r *RabbitMQ) ConsumeJobs(handler func(EvaluationJob)) {
-	msgs, err := r.channel.Consume(
-		r.queue.Name,
-		"",
-		true,  // auto-ack
-		false, // exclusive
-		false, // no-local
-		false, // no-wait
-		nil,
-	)
-	if err != nil {
-		log.Fatalf("failed to register consumer: %v", err)
-	}
+	msgs := r.consume(r.queue.Name, true)
 
 	go func() {
 		for d := range msgs {
@@ -110,3 +133,57 @@ func (r *RabbitMQ) ConsumeJobs(handler func(EvaluationJob)) {
 		}
 	}()
 }
+
+// Consume job perbandingan dari comparison queue (untuk worker)
+func (r *RabbitMQ) ConsumeComparisons(handler func(ComparisonJob)) {
+	msgs := r.consume(r.comparisonQueue.Name, true)
+
+	go func() {
+		for d := range msgs {
+			var job ComparisonJob
+			if err := json.Unmarshal(d.Body, &job); err != nil {
+				log.Printf("invalid comparison job format: %v", err)
+				continue
+			}
+			handler(job)
+		}
+	}()
+}
+
+// Consume job ekstraksi dari extraction queue (untuk worker). Pesan baru di-ack setelah
+// handler selesai (status upload sudah ditulis ke DB), jadi kalau proses crash / restart di
+// tengah ekstraksi, RabbitMQ mengirim ulang job-nya dan upload tidak tertahan di "extracting".
+func (r *RabbitMQ) ConsumeExtractions(handler func(ExtractionJob)) {
+	msgs := r.consume(r.extractionQueue.Name, false)
+
+	go func() {
+		for d := range msgs {
+			var job ExtractionJob
+			if err := json.Unmarshal(d.Body, &job); err != nil {
+				log.Printf("invalid extraction job format: %v", err)
+				d.Nack(false, false) // buang, format salah tidak akan berhasil kalau diulang
+				continue
+			}
+			handler(job)
+			if err := d.Ack(false); err != nil {
+				log.Printf("failed to ack extraction job for upload %d: %v", job.UploadID, err)
+			}
+		}
+	}()
+}
+
+func (r *RabbitMQ) consume(queueName string, autoAck bool) <-chan amqp.Delivery {
+	msgs, err := r.channel.Consume(
+		queueName,
+		"",
+		autoAck,
+		false, // exclusive
+		false, // no-local
+		false, // no-wait
+		nil,
+	)
+	if err != nil {
+		log.Fatalf("failed to register consumer for %s: %v", queueName, err)
+	}
+	return msgs
+}
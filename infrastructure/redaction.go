@@ -0,0 +1,155 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"cv-evaluator/domain"
+)
+
+// LoadRedactionMode membaca PII_REDACTION: "on" (default) meredaksi PII sebelum teks kandidat
+// dikirim ke LLM, "off" mengirim teks apa adanya. Job dengan blind review selalu memakai
+// mode blind.
+func LoadRedactionMode() string {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("PII_REDACTION"))) {
+	case "off", "false", "0":
+		return domain.RedactionNone
+	default:
+		return domain.RedactionPII
+	}
+}
+
+// RedactionModeFor: mode redaksi untuk evaluasi / perbandingan sebuah job
+func RedactionModeFor(job domain.Job, defaultMode string) string {
+	if job.BlindReview {
+		return domain.RedactionBlind
+	}
+	return defaultMode
+}
+
+// PrepareCandidateDocuments memuat input prompt sebuah upload lalu meredaksinya sesuai mode.
+// Mapping placeholder disimpan di server; ID-nya dikembalikan (nil kalau tidak ada yang
+// diredaksi) supaya feedback model bisa dikembalikan ke teks asli. prefix membedakan placeholder
+// kandidat di prompt yang memuat lebih dari satu kandidat (lihat Redactor.WithPrefix).
+func PrepareCandidateDocuments(db *gorm.DB, upload domain.Upload, mode, prefix string) (CandidateDocuments, *uint, error) {
+	docs, err := LoadCandidateDocuments(db, upload)
+	if err != nil || mode == domain.RedactionNone {
+		return docs, nil, err
+	}
+
+	redactor, err := RedactCandidateDocuments(db, upload, &docs, mode, prefix)
+	if err != nil {
+		return docs, nil, err
+	}
+	mappingID, err := SaveRedactionMapping(db, upload.ID, mode, redactor.Entries())
+	if err != nil {
+		return docs, nil, err
+	}
+	return docs, mappingID, nil
+}
+
+// RedactCandidateDocuments meredaksi teks CV, teks project dan link di docs. Nama kandidat
+// diambil dari data kandidat dan hasil parsing CV.
+func RedactCandidateDocuments(db *gorm.DB, upload domain.Upload, docs *CandidateDocuments, mode, prefix string) (*domain.Redactor, error) {
+	names, err := candidateNames(db, upload)
+	if err != nil {
+		return nil, err
+	}
+	redactor := domain.NewRedactor(mode, names).WithPrefix(prefix)
+	docs.CVText = redactor.Redact(docs.CVText)
+	docs.ProjectText = redactor.Redact(docs.ProjectText)
+
+	links := make([]domain.DocumentLink, len(docs.Links))
+	for i, l := range docs.Links {
+		l.URL = redactor.Redact(l.URL)
+		links[i] = l
+	}
+	docs.Links = links
+	return redactor, nil
+}
+
+// RedactCVForParsing meredaksi teks CV sebelum dikirim ke prompt parsing CV. Parsing tidak
+// terikat job, jadi kalau redaksi aktif selalu memakai mode blind (parser tidak butuh usia,
+// agama, dsb.). Entries dipakai SaveCVProfile untuk mengembalikan nilai asli ke hasil parsing.
+func RedactCVForParsing(db *gorm.DB, upload domain.Upload) (string, []domain.RedactionEntry, error) {
+	if LoadRedactionMode() == domain.RedactionNone {
+		return upload.CVText, nil, nil
+	}
+	names, err := candidateNames(db, upload)
+	if err != nil {
+		return "", nil, err
+	}
+	redactor := domain.NewRedactor(domain.RedactionBlind, names)
+	return redactor.Redact(upload.CVText), redactor.Entries(), nil
+}
+
+func candidateNames(db *gorm.DB, upload domain.Upload) ([]string, error) {
+	var names []string
+	if upload.CandidateID != nil {
+		var candidate domain.Candidate
+		if err := db.Select("id", "name").Limit(1).Find(&candidate, *upload.CandidateID).Error; err != nil {
+			return nil, fmt.Errorf("failed to load candidate for upload %d: %w", upload.ID, err)
+		}
+		names = append(names, candidate.Name)
+	}
+	var profile domain.CVProfile
+	if err := db.Select("id", "full_name").Where("upload_id = ?", upload.ID).Limit(1).Find(&profile).Error; err != nil {
+		return nil, fmt.Errorf("failed to load cv profile for upload %d: %w", upload.ID, err)
+	}
+	return append(names, profile.FullName), nil
+}
+
+// SaveRedactionMapping menyimpan mapping placeholder. Mapping terakhir upload + mode yang
+// isinya sama dipakai ulang.
+func SaveRedactionMapping(db *gorm.DB, uploadID uint, mode string, entries []domain.RedactionEntry) (*uint, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	entriesJSON, _ := json.Marshal(entries)
+
+	var latest domain.RedactionMapping
+	if err := db.Where("upload_id = ? AND mode = ?", uploadID, mode).Order("id DESC").Limit(1).Find(&latest).Error; err != nil {
+		return nil, fmt.Errorf("failed to load redaction mapping: %w", err)
+	}
+	if latest.ID != 0 && sameEntries(latest.Entries, entries) {
+		return &latest.ID, nil
+	}
+
+	mapping := domain.RedactionMapping{UploadID: uploadID, Mode: mode, Entries: string(entriesJSON)}
+	if err := db.Create(&mapping).Error; err != nil {
+		return nil, fmt.Errorf("failed to save redaction mapping: %w", err)
+	}
+	return &mapping.ID, nil
+}
+
+// LoadRedactionEntries memuat mapping placeholder → teks asli
+func LoadRedactionEntries(db *gorm.DB, mappingID uint) ([]domain.RedactionEntry, error) {
+	var mapping domain.RedactionMapping
+	if err := db.First(&mapping, mappingID).Error; err != nil {
+		return nil, fmt.Errorf("failed to load redaction mapping %d: %w", mappingID, err)
+	}
+	var entries []domain.RedactionEntry
+	if err := json.Unmarshal([]byte(mapping.Entries), &entries); err != nil {
+		return nil, fmt.Errorf("invalid redaction mapping %d: %w", mappingID, err)
+	}
+	return entries, nil
+}
+
+// sameEntries membandingkan isi JSON tersimpan dengan entries baru (kolom JSON MySQL bisa
+// mengubah format teksnya, jadi dibandingkan setelah di-decode)
+func sameEntries(stored string, entries []domain.RedactionEntry) bool {
+	var old []domain.RedactionEntry
+	if err := json.Unmarshal([]byte(stored), &old); err != nil || len(old) != len(entries) {
+		return false
+	}
+	for i := range old {
+		if old[i] != entries[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,234 @@
+package infrastructure
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Destination RTF yang isinya bukan teks dokumen
+var rtfSkipDestinations = map[string]bool{
+	"fonttbl": true, "colortbl": true, "stylesheet": true, "info": true,
+	"pict": true, "object": true, "themedata": true, "colorschememapping": true,
+	"datastore": true, "latentstyles": true, "listtable": true, "listoverridetable": true,
+	"rsidtbl": true, "generator": true, "xmlnstbl": true, "filetbl": true,
+	"revtbl": true, "pgdsctbl": true, "fldinst": true, "bkmkstart": true, "bkmkend": true,
+}
+
+// rtfState adalah state per group { ... }
+type rtfState struct {
+	skip     bool
+	ucSkip   int // jumlah karakter fallback setelah \uN (\ucN)
+	codepage *charmap.Charmap
+}
+
+// extractTextFromRTF converts RTF to plain text: paragraphs and line breaks are kept,
+// table cells are separated with " | ", and non-text destinations are dropped
+func extractTextFromRTF(data []byte) (string, error) {
+	s := string(data)
+	if !strings.HasPrefix(strings.TrimSpace(s), "{\\rtf") {
+		return "", fmt.Errorf("invalid RTF document")
+	}
+
+	var out strings.Builder
+	stack := []rtfState{{ucSkip: 1, codepage: charmap.Windows1252}}
+	pendingSkip := 0
+
+	for i := 0; i < len(s); {
+		cur := &stack[len(stack)-1]
+		ch := s[i]
+
+		switch ch {
+		case '{':
+			stack = append(stack, *cur)
+			i++
+			// {\* ...} = destination yang boleh diabaikan kalau tidak dikenal
+			if strings.HasPrefix(s[i:], "\\*") {
+				stack[len(stack)-1].skip = true
+				i += 2
+			}
+			continue
+		case '}':
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+			i++
+			continue
+		case '\r', '\n':
+			i++
+			continue
+		case '\\':
+			word, param, hasParam, next := readRTFControl(s, i)
+			i = next
+
+			switch word {
+			case "'":
+				// \'hh → byte di codepage aktif
+				if i+2 <= len(s) {
+					if b, err := strconv.ParseUint(s[i:i+2], 16, 8); err == nil {
+						i += 2
+						if pendingSkip > 0 {
+							pendingSkip--
+						} else if !cur.skip {
+							out.WriteRune(cur.codepage.DecodeByte(byte(b)))
+						}
+					}
+				}
+			case "u":
+				if hasParam && !cur.skip {
+					r := param
+					if r < 0 {
+						r += 65536
+					}
+					out.WriteRune(rune(r))
+				}
+				pendingSkip = cur.ucSkip
+			case "uc":
+				if hasParam {
+					cur.ucSkip = param
+				}
+			case "ansicpg":
+				if cm := rtfCodepage(param); cm != nil {
+					cur.codepage = cm
+				}
+			case "par", "line", "sect", "page":
+				if !cur.skip {
+					out.WriteString("\n")
+				}
+			case "row":
+				if !cur.skip {
+					out.WriteString("\n")
+				}
+			case "cell":
+				if !cur.skip {
+					out.WriteString(" | ")
+				}
+			case "tab":
+				if !cur.skip {
+					out.WriteString("\t")
+				}
+			case "bullet":
+				if !cur.skip {
+					out.WriteString("•")
+				}
+			case "emdash":
+				if !cur.skip {
+					out.WriteString("—")
+				}
+			case "endash":
+				if !cur.skip {
+					out.WriteString("–")
+				}
+			case "lquote", "rquote":
+				if !cur.skip {
+					out.WriteString("'")
+				}
+			case "ldblquote", "rdblquote":
+				if !cur.skip {
+					out.WriteString("\"")
+				}
+			case "\\", "{", "}":
+				if !cur.skip {
+					out.WriteString(word)
+				}
+			case "~":
+				if !cur.skip {
+					out.WriteString(" ")
+				}
+			default:
+				if rtfSkipDestinations[word] {
+					cur.skip = true
+				}
+			}
+			continue
+		}
+
+		// Karakter biasa
+		if pendingSkip > 0 {
+			pendingSkip--
+		} else if !cur.skip {
+			out.WriteByte(ch)
+		}
+		i++
+	}
+
+	text := normalizeRTFText(out.String())
+	if text == "" {
+		return "", fmt.Errorf("no text found in RTF")
+	}
+
+	fmt.Printf("Successfully extracted text from RTF (%d characters)\n", len(text))
+	return text, nil
+}
+
+// readRTFControl membaca control word / control symbol mulai dari backslash di posisi i
+func readRTFControl(s string, i int) (word string, param int, hasParam bool, next int) {
+	i++ // skip backslash
+	if i >= len(s) {
+		return "", 0, false, i
+	}
+
+	// Control symbol: backslash + satu karakter non-huruf
+	if c := s[i]; !isASCIILetter(c) {
+		return string(c), 0, false, i + 1
+	}
+
+	start := i
+	for i < len(s) && isASCIILetter(s[i]) {
+		i++
+	}
+	word = s[start:i]
+
+	numStart := i
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i > numStart {
+		if n, err := strconv.Atoi(s[numStart:i]); err == nil {
+			param, hasParam = n, true
+		}
+	}
+
+	// Satu spasi setelah control word adalah delimiter, bukan teks
+	if i < len(s) && s[i] == ' ' {
+		i++
+	}
+	return word, param, hasParam, i
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func rtfCodepage(cp int) *charmap.Charmap {
+	switch cp {
+	case 1250:
+		return charmap.Windows1250
+	case 1251:
+		return charmap.Windows1251
+	case 1252:
+		return charmap.Windows1252
+	case 1253:
+		return charmap.Windows1253
+	case 1254:
+		return charmap.Windows1254
+	case 1257:
+		return charmap.Windows1257
+	}
+	return nil
+}
+
+func normalizeRTFText(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		line = strings.TrimSuffix(line, " |")
+		lines[i] = line
+	}
+	return collapseBlankLines(lines)
+}
@@ -0,0 +1,223 @@
+package infrastructure
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"gorm.io/gorm"
+
+	"cv-evaluator/domain"
+)
+
+//go:embed skills_taxonomy.yaml
+var bundledSkillTaxonomy []byte
+
+// ErrSkillConflict dikembalikan kalau nama / alias skill sudah dipakai skill lain
+var ErrSkillConflict = errors.New("skill name or alias already used by another skill")
+
+// SkillInput adalah definisi satu skill, dari file taxonomy bawaan atau dari API
+type SkillInput struct {
+	Name     string   `yaml:"name" json:"name"`
+	Category string   `yaml:"category" json:"category"`
+	Parent   string   `yaml:"parent" json:"parent"`
+	Aliases  []string `yaml:"aliases" json:"aliases"`
+}
+
+// seedSkillTaxonomy mengisi tabel skills dari skills_taxonomy.yaml kalau masih kosong
+func seedSkillTaxonomy(db *gorm.DB) {
+	var count int64
+	if err := db.Model(&domain.Skill{}).Count(&count).Error; err != nil {
+		log.Fatalf("failed to count skills: %v", err)
+	}
+	if count > 0 {
+		return
+	}
+
+	var file struct {
+		Skills []SkillInput `yaml:"skills"`
+	}
+	if err := yaml.Unmarshal(bundledSkillTaxonomy, &file); err != nil {
+		log.Fatalf("invalid bundled skills taxonomy: %v", err)
+	}
+
+	// Dua tahap: semua skill dibuat dulu, baru parent di-link (parent bisa ditulis belakangan)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, in := range file.Skills {
+			if _, err := SaveSkill(tx, nil, SkillInput{Name: in.Name, Category: in.Category, Aliases: in.Aliases}); err != nil {
+				return fmt.Errorf("%s: %w", in.Name, err)
+			}
+		}
+		for _, in := range file.Skills {
+			if in.Parent == "" {
+				continue
+			}
+			var skill domain.Skill
+			if err := tx.Where("name = ?", in.Name).First(&skill).Error; err != nil {
+				return err
+			}
+			if _, err := SaveSkill(tx, &skill, in); err != nil {
+				return fmt.Errorf("%s: %w", in.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("failed to seed skills taxonomy: %v", err)
+	}
+	fmt.Printf("✅ Seeded %d skills into taxonomy\n", len(file.Skills))
+}
+
+// LoadSkillTaxonomy memuat semua skill + alias dari database jadi index normalisasi
+func LoadSkillTaxonomy(db *gorm.DB) (*domain.SkillTaxonomy, error) {
+	var skills []domain.Skill
+	if err := db.Preload("Aliases").Order("id").Find(&skills).Error; err != nil {
+		return nil, fmt.Errorf("failed to load skills taxonomy: %w", err)
+	}
+	return domain.NewSkillTaxonomy(skills), nil
+}
+
+// SaveSkill membuat skill baru (existing nil) atau mengganti nama, kategori, parent dan
+// alias skill yang sudah ada. Nama dan alias tidak boleh bentrok dengan skill lain.
+func SaveSkill(db *gorm.DB, existing *domain.Skill, in SkillInput) (domain.Skill, error) {
+	var skill domain.Skill
+	if existing != nil {
+		skill = *existing
+	}
+	skill.Name = strings.TrimSpace(in.Name)
+	skill.MatchKey = domain.SkillKey(skill.Name)
+	skill.Category = strings.TrimSpace(in.Category)
+	if skill.MatchKey == "" || skill.Category == "" {
+		return skill, errors.New("name and category are required")
+	}
+
+	// Alias yang sama (setelah dinormalisasi) dengan nama atau alias lain cukup sekali
+	var aliases []domain.SkillAlias
+	seen := map[string]bool{skill.MatchKey: true}
+	for _, a := range in.Aliases {
+		key := domain.SkillKey(a)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		aliases = append(aliases, domain.SkillAlias{Alias: strings.TrimSpace(a), MatchKey: key})
+	}
+
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	var skillConflicts, aliasConflicts int64
+	if err := db.Model(&domain.Skill{}).Where("match_key IN ? AND id <> ?", keys, skill.ID).Count(&skillConflicts).Error; err != nil {
+		return skill, err
+	}
+	if err := db.Model(&domain.SkillAlias{}).Where("match_key IN ? AND skill_id <> ?", keys, skill.ID).Count(&aliasConflicts).Error; err != nil {
+		return skill, err
+	}
+	if skillConflicts+aliasConflicts > 0 {
+		return skill, ErrSkillConflict
+	}
+
+	skill.ParentID, skill.Parent = nil, nil
+	if parent := strings.TrimSpace(in.Parent); parent != "" {
+		var p domain.Skill
+		if err := db.Where("match_key = ?", domain.SkillKey(parent)).First(&p).Error; err != nil {
+			return skill, fmt.Errorf("parent skill %q not found", parent)
+		}
+		if p.ID == skill.ID {
+			return skill, errors.New("a skill cannot be its own parent")
+		}
+		skill.ParentID = &p.ID
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		skill.Aliases = nil
+		if err := tx.Save(&skill).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("skill_id = ?", skill.ID).Delete(&domain.SkillAlias{}).Error; err != nil {
+			return err
+		}
+		for i := range aliases {
+			aliases[i].SkillID = skill.ID
+		}
+		if len(aliases) > 0 {
+			if err := tx.Create(&aliases).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	skill.Aliases = aliases
+	return skill, err
+}
+
+// RenormalizeSkills menerapkan taxonomy terbaru ke skill CV dan pengalaman per skill yang
+// sudah tersimpan, supaya filter dan skill overlap langsung mengikuti perubahan taxonomy
+func RenormalizeSkills(db *gorm.DB) error {
+	taxonomy, err := LoadSkillTaxonomy(db)
+	if err != nil {
+		return err
+	}
+
+	var skills []domain.CVSkill
+	err = db.FindInBatches(&skills, 500, func(tx *gorm.DB, batch int) error {
+		for _, s := range skills {
+			skillID, normalized := canonicalSkill(taxonomy, s.Name)
+			if normalized == s.NormalizedName && equalIDs(skillID, s.SkillID) {
+				continue
+			}
+			if err := db.Model(&domain.CVSkill{}).Where("id = ?", s.ID).Updates(map[string]interface{}{
+				"normalized_name": normalized,
+				"skill_id":        skillID,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to renormalize cv skills: %w", err)
+	}
+
+	var experiences []domain.SkillExperience
+	err = db.FindInBatches(&experiences, 500, func(tx *gorm.DB, batch int) error {
+		for _, e := range experiences {
+			name := taxonomy.Canonical(e.Name)
+			if name == e.Name {
+				continue
+			}
+			if err := db.Model(&domain.SkillExperience{}).Where("id = ?", e.ID).Updates(map[string]interface{}{
+				"skill": domain.NormalizeSkill(name),
+				"name":  clip(name, 128),
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to renormalize skill experience: %w", err)
+	}
+	return nil
+}
+
+// canonicalSkill: ID skill di taxonomy (nil kalau tidak dikenal) dan nama yang dipakai
+// untuk filter (nama kanonik yang dinormalisasi)
+func canonicalSkill(taxonomy *domain.SkillTaxonomy, name string) (*uint, string) {
+	if s, ok := taxonomy.Lookup(name); ok {
+		id := s.ID
+		return &id, domain.NormalizeSkill(s.Name)
+	}
+	return nil, domain.NormalizeSkill(name)
+}
+
+func equalIDs(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,492 @@
+package infrastructure
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"cv-evaluator/domain"
+)
+
+const (
+	MIMEZip  = "application/zip"
+	MIMEGzip = "application/gzip"
+
+	sourceArchiveMethod = "source-archive"
+)
+
+// Batas isi source archive setelah decompress. File besar (dataset, asset) tetap diterima,
+// tapi hanya file teks yang dianalisis.
+var sourceArchiveLimits = ArchiveLimits{
+	MaxFiles:     5000,
+	MaxFileSize:  50 << 20,
+	MaxTotalSize: 200 << 20,
+}
+
+// IsSourceArchive: archive (ZIP / tar.gz) hanya diterima sebagai project deliverable
+func IsSourceArchive(mimeType string) bool {
+	return mimeType == MIMEZip || mimeType == MIMEGzip
+}
+
+// LoadSourceTokenBudget baca SOURCE_TOKEN_BUDGET: perkiraan jumlah token isi file yang
+// dikirim ke model dari satu source archive (default 12000)
+func LoadSourceTokenBudget() int {
+	if n, err := strconv.Atoi(os.Getenv("SOURCE_TOKEN_BUDGET")); err == nil && n > 0 {
+		return n
+	}
+	return 12000
+}
+
+// SourceAnalysis adalah metrik offline dari source archive project deliverable
+type SourceAnalysis struct {
+	Files          int             `json:"files"`           // semua file di archive
+	IgnoredFiles   int             `json:"ignored_files"`   // dependency / build output (node_modules, vendor, dist, ...)
+	BinaryFiles    int             `json:"binary_files"`    // file non-teks
+	Lines          int             `json:"lines"`           // baris di file source
+	Languages      []LanguageStats `json:"languages"`       // urut dari baris terbanyak
+	SourceFiles    int             `json:"source_files"`    // file dengan bahasa yang dikenal, termasuk test
+	TestFiles      int             `json:"test_files"`      // file test
+	TestFileRatio  float64         `json:"test_file_ratio"` // test_files / source_files
+	Readme         string          `json:"readme,omitempty"`
+	ReadmeLines    int             `json:"readme_lines,omitempty"`
+	Manifests      []string        `json:"dependency_manifests"`
+	CI             []string        `json:"ci_configs"`
+	Docker         []string        `json:"docker_files"`
+	GoComplexity   *GoComplexity   `json:"go_complexity,omitempty"`
+	SelectedFiles  []string        `json:"selected_files"` // file yang isinya dikirim ke model
+	TruncatedFiles []string        `json:"truncated_files,omitempty"`
+	OmittedFiles   int             `json:"omitted_files"` // file teks yang tidak muat di token budget
+	TokenBudget    int             `json:"token_budget"`
+	Tokens         int             `json:"estimated_tokens"` // perkiraan token isi file yang dipilih
+}
+
+// LanguageStats adalah jumlah file dan baris per bahasa
+type LanguageStats struct {
+	Language string `json:"language"`
+	Files    int    `json:"files"`
+	Lines    int    `json:"lines"`
+}
+
+// Ekstensi file source → bahasa
+var sourceLanguages = map[string]string{
+	".go": "Go", ".py": "Python", ".js": "JavaScript", ".jsx": "JavaScript", ".mjs": "JavaScript",
+	".ts": "TypeScript", ".tsx": "TypeScript", ".java": "Java", ".kt": "Kotlin", ".scala": "Scala",
+	".rb": "Ruby", ".php": "PHP", ".cs": "C#", ".c": "C", ".h": "C", ".cpp": "C++", ".cc": "C++",
+	".hpp": "C++", ".rs": "Rust", ".swift": "Swift", ".dart": "Dart", ".ex": "Elixir", ".exs": "Elixir",
+	".vue": "Vue", ".svelte": "Svelte", ".sql": "SQL", ".sh": "Shell", ".html": "HTML", ".css": "CSS",
+	".scss": "CSS",
+}
+
+// File manifest dependency yang dikenali
+var dependencyManifests = map[string]bool{
+	"go.mod": true, "package.json": true, "requirements.txt": true, "pyproject.toml": true,
+	"pipfile": true, "setup.py": true, "pom.xml": true, "build.gradle": true, "build.gradle.kts": true,
+	"cargo.toml": true, "composer.json": true, "gemfile": true, "mix.exs": true, "pubspec.yaml": true,
+}
+
+// Folder dependency / build output yang tidak ikut dianalisis
+var ignoredSourceDirs = map[string]bool{
+	"node_modules": true, "vendor": true, "dist": true, "build": true, "target": true, "bin": true,
+	"obj": true, ".venv": true, "venv": true, "__pycache__": true, ".idea": true, ".vscode": true,
+	".next": true, "coverage": true,
+}
+
+// Folder yang isinya dianggap test
+var testDirs = map[string]bool{"test": true, "tests": true, "__tests__": true, "spec": true}
+
+// sourceFile adalah satu file teks di archive beserta klasifikasinya
+type sourceFile struct {
+	Path       string
+	Text       string
+	Lines      int
+	Language   string
+	Test       bool
+	Complexity int // total complexity fungsi Go di file ini
+}
+
+// extractSourceArchive membuka ZIP / tar.gz di memory, menghitung metrik dan memilih
+// file yang paling representatif sampai token budget habis. Teks hasilnya = ringkasan
+// metrik + isi file terpilih, jadi ikut terkirim ke tahap evaluasi project.
+func (g *GeminiClient) extractSourceArchive(data []byte) (*ExtractionResult, error) {
+	files, err := ReadArchive(data, sourceArchiveLimits)
+	if err != nil {
+		return &ExtractionResult{Method: sourceArchiveMethod}, fmt.Errorf("failed to read source archive: %w", err)
+	}
+	files = stripCommonRoot(files)
+
+	analysis, sources := analyzeSource(files)
+	body := selectSourceFiles(analysis, sources, g.sourceTokens)
+
+	result := newExtractionResult(sourceArchiveMethod, analysis.Summary()+"\n\n"+body)
+	result.Source = analysis
+	if analysis.SourceFiles == 0 {
+		result.warn("archive does not contain any recognized source files")
+	}
+
+	// Link hanya diambil dari README; URL di source code (import path, endpoint contoh)
+	// bukan link profil kandidat
+	result.skipTextLinks = true
+	for _, f := range sources {
+		if f.Path == analysis.Readme {
+			for _, m := range textURLPattern.FindAllString(f.Text, -1) {
+				result.addLink(m, domain.LinkSourceText, 0)
+			}
+		}
+	}
+	return result, nil
+}
+
+// stripCommonRoot membuang folder root yang sama di semua file ("repo-main/...") supaya
+// path di analisis relatif terhadap root project
+func stripCommonRoot(files []ArchiveFile) []ArchiveFile {
+	if len(files) == 0 {
+		return files
+	}
+	root, _, ok := strings.Cut(files[0].Path, "/")
+	if !ok {
+		return files
+	}
+	for _, f := range files {
+		if !strings.HasPrefix(f.Path, root+"/") {
+			return files
+		}
+	}
+	out := make([]ArchiveFile, len(files))
+	for i, f := range files {
+		out[i] = ArchiveFile{Path: strings.TrimPrefix(f.Path, root+"/"), Data: f.Data}
+	}
+	return out
+}
+
+func analyzeSource(files []ArchiveFile) (*SourceAnalysis, []sourceFile) {
+	a := &SourceAnalysis{Files: len(files), Manifests: []string{}, CI: []string{}, Docker: []string{}}
+	languages := map[string]*LanguageStats{}
+	fset := token.NewFileSet()
+	var goFuncs []FunctionComplexity
+	goParseErrors := 0
+
+	var sources []sourceFile
+	for _, f := range files {
+		if isIgnoredSourcePath(f.Path) {
+			a.IgnoredFiles++
+			continue
+		}
+		if !isTextFile(f.Data) {
+			a.BinaryFiles++
+			continue
+		}
+
+		text := strings.ReplaceAll(string(f.Data), "\r\n", "\n")
+		sf := sourceFile{Path: f.Path, Text: text, Lines: countLines(text)}
+		base := strings.ToLower(path.Base(f.Path))
+
+		switch {
+		case strings.HasPrefix(base, "readme"):
+			// README paling dekat ke root yang dipakai
+			if a.Readme == "" || strings.Count(f.Path, "/") < strings.Count(a.Readme, "/") {
+				a.Readme, a.ReadmeLines = f.Path, sf.Lines
+			}
+		case dependencyManifests[base] || strings.HasSuffix(base, ".csproj"):
+			a.Manifests = append(a.Manifests, f.Path)
+		case strings.HasPrefix(f.Path, ".github/workflows/"), base == ".gitlab-ci.yml", base == "jenkinsfile",
+			strings.HasPrefix(f.Path, ".circleci/"):
+			a.CI = append(a.CI, f.Path)
+		case base == "dockerfile", strings.HasPrefix(base, "docker-compose"), strings.HasPrefix(base, "compose."):
+			a.Docker = append(a.Docker, f.Path)
+		}
+
+		if lang, ok := sourceLanguages[strings.ToLower(path.Ext(f.Path))]; ok {
+			sf.Language = lang
+			sf.Test = isTestFile(f.Path)
+			a.SourceFiles++
+			a.Lines += sf.Lines
+			if sf.Test {
+				a.TestFiles++
+			}
+			if languages[lang] == nil {
+				languages[lang] = &LanguageStats{Language: lang}
+			}
+			languages[lang].Files++
+			languages[lang].Lines += sf.Lines
+
+			if lang == "Go" && !sf.Test {
+				funcs, err := goFileComplexity(fset, f.Path, f.Data)
+				if err != nil {
+					goParseErrors++
+				}
+				for _, fn := range funcs {
+					sf.Complexity += fn.Complexity
+				}
+				goFuncs = append(goFuncs, funcs...)
+			}
+		}
+		sources = append(sources, sf)
+	}
+
+	for _, l := range languages {
+		a.Languages = append(a.Languages, *l)
+	}
+	sort.Slice(a.Languages, func(i, j int) bool {
+		if a.Languages[i].Lines != a.Languages[j].Lines {
+			return a.Languages[i].Lines > a.Languages[j].Lines
+		}
+		return a.Languages[i].Language < a.Languages[j].Language
+	})
+	if a.SourceFiles > 0 {
+		a.TestFileRatio = float64(a.TestFiles) / float64(a.SourceFiles)
+	}
+	if languages["Go"] != nil {
+		a.GoComplexity = summarizeGoComplexity(goFuncs, goParseErrors)
+	}
+	return a, sources
+}
+
+func isIgnoredSourcePath(p string) bool {
+	for _, part := range strings.Split(path.Dir(p), "/") {
+		if ignoredSourceDirs[strings.ToLower(part)] {
+			return true
+		}
+	}
+	return false
+}
+
+// isTextFile: UTF-8 valid dan tidak ada NUL byte di 8 KB pertama
+func isTextFile(data []byte) bool {
+	head := data
+	if len(head) > 8<<10 {
+		head = head[:8<<10]
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return false
+	}
+	return utf8.Valid(data)
+}
+
+func isTestFile(p string) bool {
+	for _, part := range strings.Split(path.Dir(p), "/") {
+		if testDirs[strings.ToLower(part)] {
+			return true
+		}
+	}
+	base := path.Base(p)
+	name := strings.TrimSuffix(base, path.Ext(base))
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "_test"), strings.HasPrefix(lower, "test_"),
+		strings.HasSuffix(lower, ".test"), strings.HasSuffix(lower, ".spec"),
+		strings.HasSuffix(name, "Test"), strings.HasSuffix(name, "Tests"):
+		return true
+	}
+	return false
+}
+
+func countLines(text string) int {
+	if text == "" {
+		return 0
+	}
+	n := strings.Count(text, "\n")
+	if !strings.HasSuffix(text, "\n") {
+		n++
+	}
+	return n
+}
+
+// estimateTokens: perkiraan token yang sama dengan budget prompt (lihat domain.EstimateTokens)
+func estimateTokens(text string) int {
+	return domain.EstimateTokens(text)
+}
+
+// Prioritas file saat dipilih, makin kecil makin dulu
+const (
+	priorityReadme = iota
+	priorityManifest
+	priorityEntrypoint
+	priorityTest
+	prioritySource
+	priorityConfig
+)
+
+// sourcePriority menentukan urutan pemilihan file. Satu file test (yang terbesar) dinaikkan
+// prioritasnya supaya testability bisa dinilai; file test lain masuk setelah source biasa.
+func sourcePriority(a *SourceAnalysis, f sourceFile, firstTest bool) int {
+	base := strings.ToLower(path.Base(f.Path))
+	name := strings.TrimSuffix(base, path.Ext(base))
+	switch {
+	case f.Path == a.Readme:
+		return priorityReadme
+	case containsString(a.Manifests, f.Path):
+		return priorityManifest
+	case f.Language == "":
+		if containsString(a.CI, f.Path) || containsString(a.Docker, f.Path) {
+			return priorityConfig
+		}
+		return -1 // dokumen / config lain tidak dikirim
+	case f.Test && firstTest:
+		return priorityTest
+	case f.Test:
+		return priorityConfig
+	case strings.HasPrefix(f.Path, "cmd/"), name == "main", name == "app", name == "index", name == "server":
+		return priorityEntrypoint
+	}
+	return prioritySource
+}
+
+// selectSourceFiles memilih isi file yang dikirim ke model: README, manifest, entrypoint,
+// satu file test, lalu file source lain (yang dekat ke root dan paling kompleks dulu),
+// terakhir config CI / Docker. Satu file maksimal 1/4 budget supaya tidak ada yang mendominasi.
+func selectSourceFiles(a *SourceAnalysis, sources []sourceFile, budget int) string {
+	a.TokenBudget = budget
+	a.SelectedFiles = []string{}
+
+	largestTest := -1
+	for i, f := range sources {
+		if f.Test && f.Language != "" && (largestTest == -1 || f.Lines > sources[largestTest].Lines) {
+			largestTest = i
+		}
+	}
+
+	type candidate struct {
+		file     sourceFile
+		priority int
+	}
+	var candidates []candidate
+	for i, f := range sources {
+		if p := sourcePriority(a, f, i == largestTest); p >= 0 {
+			candidates = append(candidates, candidate{f, p})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ci, cj := candidates[i], candidates[j]
+		if ci.priority != cj.priority {
+			return ci.priority < cj.priority
+		}
+		di, dj := strings.Count(ci.file.Path, "/"), strings.Count(cj.file.Path, "/")
+		if di != dj {
+			return di < dj
+		}
+		if ci.file.Complexity != cj.file.Complexity {
+			return ci.file.Complexity > cj.file.Complexity
+		}
+		if ci.file.Lines != cj.file.Lines {
+			return ci.file.Lines > cj.file.Lines
+		}
+		return ci.file.Path < cj.file.Path
+	})
+
+	perFile := budget / 4
+	remaining := budget
+	var b strings.Builder
+	for _, c := range candidates {
+		content := c.file.Text
+		limit := min(perFile, remaining)
+		if limit < 200 {
+			a.OmittedFiles++
+			continue
+		}
+		truncated := false
+		if estimateTokens(content) > limit {
+			content = truncateLines(content, limit*4)
+			truncated = true
+		}
+
+		header := c.file.Path
+		if c.file.Language != "" {
+			header = fmt.Sprintf("%s (%s, %d lines)", c.file.Path, c.file.Language, c.file.Lines)
+		}
+		fmt.Fprintf(&b, "=== File: %s ===\n%s\n", header, strings.TrimRight(content, "\n"))
+		if truncated {
+			fmt.Fprintf(&b, "... (truncated, %d of %d lines shown)\n", countLines(content), c.file.Lines)
+			a.TruncatedFiles = append(a.TruncatedFiles, c.file.Path)
+		}
+		b.WriteString("\n")
+
+		tokens := estimateTokens(content)
+		remaining -= tokens
+		a.Tokens += tokens
+		a.SelectedFiles = append(a.SelectedFiles, c.file.Path)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// truncateLines memotong teks di batas baris terakhir sebelum maxChars
+func truncateLines(text string, maxChars int) string {
+	if len(text) <= maxChars {
+		return text
+	}
+	cut := text[:maxChars]
+	if i := strings.LastIndexByte(cut, '\n'); i > 0 {
+		cut = cut[:i+1]
+	}
+	return strings.ToValidUTF8(cut, "")
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary menyusun metrik jadi teks ringkas untuk prompt evaluasi project
+func (a *SourceAnalysis) Summary() string {
+	var b strings.Builder
+	b.WriteString("=== Source Archive Analysis (computed offline) ===\n")
+	fmt.Fprintf(&b, "Files: %d total, %d source files, %d lines of source", a.Files, a.SourceFiles, a.Lines)
+	if a.IgnoredFiles > 0 || a.BinaryFiles > 0 {
+		fmt.Fprintf(&b, " (%d dependency/build files ignored, %d binary files)", a.IgnoredFiles, a.BinaryFiles)
+	}
+	b.WriteString("\n")
+
+	if len(a.Languages) > 0 {
+		parts := make([]string, 0, len(a.Languages))
+		for _, l := range a.Languages {
+			parts = append(parts, fmt.Sprintf("%s %d files / %d lines", l.Language, l.Files, l.Lines))
+		}
+		fmt.Fprintf(&b, "Languages: %s\n", strings.Join(parts, "; "))
+	}
+	fmt.Fprintf(&b, "Tests: %d test files (test file ratio %.2f)\n", a.TestFiles, a.TestFileRatio)
+	if a.Readme != "" {
+		fmt.Fprintf(&b, "README: yes (%s, %d lines)\n", a.Readme, a.ReadmeLines)
+	} else {
+		b.WriteString("README: no\n")
+	}
+	fmt.Fprintf(&b, "Dependency manifests: %s\n", listOrNone(a.Manifests))
+	fmt.Fprintf(&b, "CI configuration: %s\n", listOrNone(a.CI))
+	fmt.Fprintf(&b, "Docker: %s\n", listOrNone(a.Docker))
+
+	if c := a.GoComplexity; c != nil {
+		fmt.Fprintf(&b, "Go cyclomatic complexity: %d functions, average %.1f, max %d, %d above %d",
+			c.Functions, c.Average, c.Max, c.OverThreshold, c.Threshold)
+		if c.ParseErrors > 0 {
+			fmt.Fprintf(&b, ", %d files failed to parse", c.ParseErrors)
+		}
+		b.WriteString("\n")
+		for _, f := range c.Top {
+			if f.Complexity <= c.Threshold {
+				break
+			}
+			fmt.Fprintf(&b, "  - %s (%s:%d): %d\n", f.Function, f.File, f.Line, f.Complexity)
+		}
+	}
+
+	fmt.Fprintf(&b, "Files included below: %d", len(a.SelectedFiles))
+	if a.OmittedFiles > 0 || len(a.TruncatedFiles) > 0 {
+		fmt.Fprintf(&b, " (%d truncated, %d omitted to fit the token budget)", len(a.TruncatedFiles), a.OmittedFiles)
+	}
+	return b.String()
+}
+
+func listOrNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
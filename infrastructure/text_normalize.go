@@ -0,0 +1,205 @@
+package infrastructure
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// "3", "- 3 -", "Page 3", "Page 3 of 5", "3 / 5", "Halaman 3". Angka tanpa keterangan
+	// maksimal 3 digit supaya baris tahun ("2021", "2019 / 2021") tidak ikut dibuang.
+	pageNumberLine = regexp.MustCompile(`(?i)^[\s\-–—]*((page|halaman|hal\.?|p\.)\s*\d{1,4}(\s*(of|dari|/)\s*\d{1,4})?|\d{1,3}(\s*(of|dari|/)\s*\d{1,3})?)[\s\-–—]*$`)
+	digitRun       = regexp.MustCompile(`\d+`)
+	hyphenBreak    = regexp.MustCompile(`(\p{L}[\p{L}-]*)[-\x{2010}]\n(\p{Ll}+)`)
+	multiSpace     = regexp.MustCompile(`[ \t]{2,}`)
+	bulletLine     = regexp.MustCompile(`^\s*([-*•●▪■◦‣–]|\d{1,2}[.)]|[a-z][.)])\s+`)
+)
+
+// Jumlah baris teratas / terbawah tiap halaman yang dicek sebagai header/footer
+const furnitureLines = 3
+
+// normalizeText membersihkan hasil ekstraksi dari karakter yang mengganggu: ligature dan
+// karakter full-width dinormalisasi (NFKC), soft hyphen / zero-width dihapus,
+// non-breaking space jadi spasi biasa, dan control character dibuang
+func normalizeText(s string) string {
+	s = norm.NFKC.String(strings.ReplaceAll(s, "\r\n", "\n"))
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case r == '\u00ad', r == '\u200b', r == '\u200c', r == '\u200d', r == '\u2060', r == '\ufeff':
+			// soft hyphen & zero-width characters
+		case r == '\u00a0', r == '\u2007', r == '\u202f':
+			b.WriteRune(' ')
+		case r == '\r':
+			b.WriteRune('\n')
+		case r == '\n', r == '\t':
+			b.WriteRune(r)
+		case unicode.IsControl(r), r == utf8.RuneError:
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// normalizePDFPages menggabungkan teks per halaman jadi satu dokumen yang bersih:
+// header/footer yang berulang dan nomor halaman dibuang, kata yang terpotong tanda
+// hubung di akhir baris digabung lagi, dan baris dalam satu paragraf di-reflow
+func normalizePDFPages(pages []string) string {
+	pageLines := make([][]string, len(pages))
+	for i, p := range pages {
+		lines := strings.Split(normalizeText(p), "\n")
+		for j := range lines {
+			lines[j] = strings.TrimSpace(multiSpace.ReplaceAllString(lines[j], " "))
+		}
+		pageLines[i] = lines
+	}
+
+	pageLines = removePageFurniture(pageLines)
+
+	var all []string
+	for _, lines := range pageLines {
+		all = append(all, lines...)
+	}
+	text := strings.Join(all, "\n")
+	text = hyphenBreak.ReplaceAllStringFunc(text, joinHyphenBreak)
+
+	return reflowParagraphs(strings.Split(text, "\n"))
+}
+
+// removePageFurniture membuang baris di bagian atas/bawah halaman yang muncul di
+// minimal setengah dari jumlah halaman (nama di header, "Confidential", dll.) dan
+// baris yang hanya berisi nomor halaman
+func removePageFurniture(pages [][]string) [][]string {
+	edgeIndexes := func(lines []string) []int {
+		var idx []int
+		for i := 0; i < len(lines) && len(idx) < furnitureLines; i++ {
+			if lines[i] != "" {
+				idx = append(idx, i)
+			}
+		}
+		var tail []int
+		for i := len(lines) - 1; i >= 0 && len(tail) < furnitureLines; i-- {
+			if lines[i] != "" {
+				tail = append(tail, i)
+			}
+		}
+		return append(idx, tail...)
+	}
+
+	// Nomor halaman di-mask supaya "Page 1" dan "Page 2" dianggap baris yang sama
+	key := func(line string) string {
+		return strings.ToLower(digitRun.ReplaceAllString(line, "#"))
+	}
+
+	counts := map[string]int{}
+	for _, lines := range pages {
+		seen := map[string]bool{}
+		for _, i := range edgeIndexes(lines) {
+			k := key(lines[i])
+			if !seen[k] {
+				seen[k] = true
+				counts[k]++
+			}
+		}
+	}
+
+	minRepeats := (len(pages) + 1) / 2
+	if minRepeats < 2 {
+		minRepeats = 2
+	}
+
+	out := make([][]string, len(pages))
+	for p, lines := range pages {
+		drop := map[int]bool{}
+		for _, i := range edgeIndexes(lines) {
+			// Baris tanpa huruf (mis. rentang tahun) hanya dibuang kalau memang nomor halaman
+			if pageNumberLine.MatchString(lines[i]) || (hasLetter(lines[i]) && counts[key(lines[i])] >= minRepeats) {
+				drop[i] = true
+			}
+		}
+		kept := make([]string, 0, len(lines))
+		for i, line := range lines {
+			if !drop[i] {
+				kept = append(kept, line)
+			}
+		}
+		out[p] = kept
+	}
+	return out
+}
+
+func hasLetter(s string) bool {
+	return strings.IndexFunc(s, unicode.IsLetter) >= 0
+}
+
+// Awalan yang hampir selalu ditulis dengan tanda hubung ("self-taught", "non-technical")
+var hyphenPrefixes = map[string]bool{
+	"self": true, "non": true, "multi": true, "cross": true, "semi": true, "anti": true, "full": true, "part": true,
+}
+
+// Kata yang sering jadi bagian kata majemuk ber-tanda hubung di CV ("back-end", "real-time",
+// "detail-oriented"). Kalau kedua sisi ada di sini, tanda hubungnya dipertahankan.
+var hyphenCompoundWords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`
+		back front end stack real time open source data driven based level scale large small
+		team player problem solving detail oriented fast paced hands on user facing world class
+		long short term high low performance quality cost effective test event machine learning
+		cloud native micro service services peer to one step web mobile first platform up down
+		date key value side client server made sized wide`) {
+		hyphenCompoundWords[w] = true
+	}
+}
+
+// joinHyphenBreak menggabungkan kata yang terpotong tanda hubung di akhir baris
+// ("develop-\nment" → "development"). Tanda hubung dipertahankan untuk kata majemuk:
+// kata kiri sudah ber-tanda hubung ("state-of-the-\nart"), berupa awalan yang dikenal
+// ("full-\nstack"), atau kedua sisinya kata utuh yang umum ("back-\nend").
+func joinHyphenBreak(match string) string {
+	m := hyphenBreak.FindStringSubmatch(match)
+	left, right := m[1], m[2]
+	lower := strings.ToLower(left)
+	if strings.Contains(left, "-") || hyphenPrefixes[lower] || (hyphenCompoundWords[lower] && hyphenCompoundWords[right]) {
+		return left + "-" + right
+	}
+	return left + right
+}
+
+// reflowParagraphs menyambung baris yang terpotong karena lebar kolom. Supaya struktur
+// résumé (judul, nama perusahaan, tanggal) tidak ikut tergabung, baris hanya disambung
+// kalau baris sebelumnya cukup panjang, tidak diakhiri tanda baca penutup, dan baris
+// berikutnya diawali huruf kecil serta bukan item list.
+func reflowParagraphs(lines []string) string {
+	var out []string
+	for _, line := range lines {
+		if n := len(out); n > 0 && canJoin(out[n-1], line) {
+			out[n-1] = out[n-1] + " " + line
+			continue
+		}
+		out = append(out, line)
+	}
+	return collapseBlankLines(out)
+}
+
+func canJoin(prev, next string) bool {
+	if prev == "" || next == "" || bulletLine.MatchString(next) {
+		return false
+	}
+	if utf8.RuneCountInString(prev) < 30 {
+		return false
+	}
+	last, _ := utf8.DecodeLastRuneInString(prev)
+	if strings.ContainsRune(".!?:;", last) {
+		return false
+	}
+	first, _ := utf8.DecodeRuneInString(next)
+	return unicode.IsLower(first) || last == ','
+}
@@ -0,0 +1,129 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrFileTooLarge     = errors.New("file too large")
+	ErrUnsupportedType  = errors.New("unsupported file type")
+	ErrFileTypeMismatch = errors.New("file content does not match its extension")
+	ErrEmptyFile        = errors.New("file is empty")
+)
+
+// UploadLimits membatasi ukuran per file dan per request upload
+type UploadLimits struct {
+	MaxFileSize    int64
+	MaxRequestSize int64
+	MaxBatchSize   int64 // ukuran archive untuk bulk upload
+	MaxBatchFiles  int   // jumlah file di dalam archive bulk upload
+}
+
+// LoadUploadLimits baca MAX_UPLOAD_FILE_MB, MAX_UPLOAD_REQUEST_MB, MAX_BATCH_UPLOAD_MB dan
+// MAX_BATCH_FILES (default 10 MB / 25 MB / 100 MB / 1000 file)
+func LoadUploadLimits() UploadLimits {
+	limits := UploadLimits{
+		MaxFileSize:    envMegabytes("MAX_UPLOAD_FILE_MB", 10),
+		MaxRequestSize: envMegabytes("MAX_UPLOAD_REQUEST_MB", 25),
+		MaxBatchSize:   envMegabytes("MAX_BATCH_UPLOAD_MB", 100),
+		MaxBatchFiles:  1000,
+	}
+	if n, err := strconv.Atoi(os.Getenv("MAX_BATCH_FILES")); err == nil && n > 0 {
+		limits.MaxBatchFiles = n
+	}
+	return limits
+}
+
+// BatchArchiveLimits: setiap file di archive dibatasi seperti upload biasa, total isi
+// archive setelah decompress maksimal 2x ukuran archive (CV / project PDF hampir tidak
+// bisa dikompres lagi, jadi rasio lebih besar hanya memberi ruang untuk zip bomb)
+func (l UploadLimits) BatchArchiveLimits() ArchiveLimits {
+	return ArchiveLimits{
+		MaxFiles:     l.MaxBatchFiles,
+		MaxFileSize:  l.MaxFileSize,
+		MaxTotalSize: 2 * l.MaxBatchSize,
+	}
+}
+
+func envMegabytes(key string, def int64) int64 {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			return n << 20
+		}
+	}
+	return def << 20
+}
+
+// Ekstensi yang dikenal → MIME type yang diharapkan dari hasil sniffing
+var extensionMIMETypes = map[string]string{
+	".txt":      MIMEPlainText,
+	".md":       MIMEMarkdown,
+	".markdown": MIMEMarkdown,
+	".htm":      MIMEHTML,
+	".html":     MIMEHTML,
+	".rtf":      MIMERTF,
+	".pdf":      MIMEPDF,
+	".docx":     MIMEDOCX,
+	".odt":      MIMEODT,
+	".png":      MIMEPNG,
+	".jpg":      MIMEJPEG,
+	".jpeg":     MIMEJPEG,
+	".webp":     MIMEWebP,
+	".zip":      MIMEZip,
+	".gz":       MIMEGzip,
+	".tgz":      MIMEGzip,
+}
+
+// UploadedDocument adalah file upload yang sudah dibaca dan divalidasi
+type UploadedDocument struct {
+	Filename string
+	MIMEType string
+	Data     []byte
+}
+
+// ReadUpload membaca file dengan batas maxSize (berhenti begitu batas terlewati, tidak
+// membaca seluruh file dulu), lalu memvalidasi type dari magic bytes terhadap allowlist
+// (format yang punya extractor) dan terhadap ekstensi filename.
+func (g *GeminiClient) ReadUpload(r io.Reader, filename string, maxSize int64) (*UploadedDocument, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: %s exceeds %d MB", ErrFileTooLarge, filename, maxSize>>20)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEmptyFile, filename)
+	}
+
+	// Type paling spesifik hasil sniffing harus ada di allowlist; parent (mis. text/plain
+	// untuk JSON) tidak dihitung. Pengecualian: subtype text/* yang tidak punya extractor
+	// (mis. .txt berisi tabel yang tersniff sebagai text/csv) dibaca sebagai text/plain.
+	candidates := DetectMIME(data, filename)
+	detected := candidates[0]
+	if !g.extractors.Has(detected) && strings.HasPrefix(detected, "text/") && slices.Contains(candidates, MIMEPlainText) {
+		detected = MIMEPlainText
+	}
+	if !g.extractors.Has(detected) {
+		return nil, fmt.Errorf("%w: %s detected as %s", ErrUnsupportedType, filename, detected)
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	if expected, known := extensionMIMETypes[ext]; known && expected != detected {
+		return nil, fmt.Errorf("%w: %s has extension %s but content is %s", ErrFileTypeMismatch, filename, ext, detected)
+	}
+
+	return &UploadedDocument{Filename: filename, MIMEType: detected, Data: data}, nil
+}
+
+// AllowedMIMETypes mengembalikan daftar MIME type yang boleh di-upload
+func (g *GeminiClient) AllowedMIMETypes() []string {
+	return g.extractors.Types()
+}
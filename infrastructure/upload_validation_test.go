@@ -0,0 +1,74 @@
+package infrastructure
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestReadUploadTextTypes(t *testing.T) {
+	g := &GeminiClient{}
+	g.extractors = g.newExtractorRegistry()
+
+	tests := []struct {
+		name     string
+		filename string
+		data     string
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "README with HTML header",
+			filename: "README.md",
+			data:     "<p align=\"center\">\n  <img src=\"logo.png\" width=\"120\">\n</p>\n<h1 align=\"center\">CV Evaluator</h1>\n\n## Setup\n\n- `go run ./cmd`\n",
+			want:     MIMEMarkdown,
+		},
+		{
+			name:     "README with centered h1",
+			filename: "readme.markdown",
+			data:     "<h1 align=\"center\">Project</h1>\n\nA *small* service.\n",
+			want:     MIMEMarkdown,
+		},
+		{
+			name:     "plain Markdown",
+			filename: "project.md",
+			data:     "# Project\n\nSome text.\n",
+			want:     MIMEMarkdown,
+		},
+		{
+			name:     "CSV-like text file",
+			filename: "report.txt",
+			data:     "name,role,years\nJane,Backend,4\nJohn,Frontend,3\n",
+			want:     MIMEPlainText,
+		},
+		{
+			name:     "HTML file",
+			filename: "cv.html",
+			data:     "<!DOCTYPE html><html><body><h1>Jane</h1></body></html>",
+			want:     MIMEHTML,
+		},
+		{
+			name:     "HTML content with .txt extension",
+			filename: "cv.txt",
+			data:     "<!DOCTYPE html><html><body><h1>Jane</h1></body></html>",
+			wantErr:  ErrFileTypeMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := g.ReadUpload(bytes.NewReader([]byte(tt.data)), tt.filename, 1<<20)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ReadUpload() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadUpload() error = %v", err)
+			}
+			if doc.MIMEType != tt.want {
+				t.Errorf("MIMEType = %s, want %s", doc.MIMEType, tt.want)
+			}
+		})
+	}
+}
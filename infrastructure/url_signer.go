@@ -0,0 +1,56 @@
+package infrastructure
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// URLSigner membuat dan memverifikasi signature untuk download URL yang berlaku singkat
+type URLSigner struct {
+	secret []byte
+}
+
+// NewURLSigner pakai FILE_URL_SECRET. Kalau tidak di-set, secret random dibuat per proses
+// (URL yang sudah dibagikan jadi tidak valid setelah restart, tapi memang berumur pendek).
+func NewURLSigner() *URLSigner {
+	secret := []byte(os.Getenv("FILE_URL_SECRET"))
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Fatalf("failed to generate URL signing secret: %v", err)
+		}
+	}
+	return &URLSigner{secret: secret}
+}
+
+// Sign mengembalikan signature untuk resource yang berlaku sampai expires
+func (s *URLSigner) Sign(resource string, expires time.Time) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%s\n%d", resource, expires.Unix())
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify cek signature dan masa berlaku (expires dalam unix seconds)
+func (s *URLSigner) Verify(resource string, expires string, signature string) error {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid expires")
+	}
+	exp := time.Unix(unix, 0)
+	if time.Now().After(exp) {
+		return fmt.Errorf("link expired")
+	}
+
+	expected := s.Sign(resource, exp)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package infrastructure
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestURLSignerVerify(t *testing.T) {
+	signer := &URLSigner{secret: []byte("test-secret")}
+	other := &URLSigner{secret: []byte("other-secret")}
+
+	valid := time.Now().Add(5 * time.Minute)
+	expired := time.Now().Add(-time.Second)
+	sig := signer.Sign("file:1", valid)
+	unix := strconv.FormatInt(valid.Unix(), 10)
+
+	// Ubah satu karakter signature
+	tampered := []byte(sig)
+	if tampered[0] == 'a' {
+		tampered[0] = 'b'
+	} else {
+		tampered[0] = 'a'
+	}
+
+	tests := []struct {
+		name      string
+		signer    *URLSigner
+		resource  string
+		expires   string
+		signature string
+		wantErr   string
+	}{
+		{"valid", signer, "file:1", unix, sig, ""},
+		{"expired", signer, "file:1", strconv.FormatInt(expired.Unix(), 10), signer.Sign("file:1", expired), "link expired"},
+		{"tampered signature", signer, "file:1", unix, string(tampered), "invalid signature"},
+		{"other resource", signer, "file:2", unix, sig, "invalid signature"},
+		{"extended expiry", signer, "file:1", strconv.FormatInt(valid.Add(time.Hour).Unix(), 10), sig, "invalid signature"},
+		{"other secret", other, "file:1", unix, sig, "invalid signature"},
+		{"empty signature", signer, "file:1", unix, "", "invalid signature"},
+		{"non-numeric expires", signer, "file:1", "tomorrow", sig, "invalid expires"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.signer.Verify(tt.resource, tt.expires, tt.signature)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Verify: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Verify: err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewURLSignerRandomSecret(t *testing.T) {
+	t.Setenv("FILE_URL_SECRET", "")
+	a, b := NewURLSigner(), NewURLSigner()
+	exp := time.Now().Add(time.Minute)
+	if a.Sign("file:1", exp) == b.Sign("file:1", exp) {
+		t.Error("signers without FILE_URL_SECRET should use different random secrets")
+	}
+
+	t.Setenv("FILE_URL_SECRET", "shared")
+	if NewURLSigner().Sign("file:1", exp) != NewURLSigner().Sign("file:1", exp) {
+		t.Error("signers with the same FILE_URL_SECRET should produce the same signature")
+	}
+}
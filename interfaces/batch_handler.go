@@ -0,0 +1,329 @@
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"cv-evaluator/domain"
+	"cv-evaluator/infrastructure"
+)
+
+// CreateBatch → bulk upload: ZIP / tar.gz berisi banyak pasangan CV + project (plus manifest.csv
+// opsional). Setiap pasangan jadi satu upload yang diekstrak di background; kalau job_id
+// diisi, evaluasi otomatis diantrekan setelah teks siap.
+func (h *HTTPHandler) CreateBatch(c *gin.Context) {
+	if !parseMultipartForm(c, h.Limits.MaxBatchSize) {
+		return
+	}
+
+	var jobID *uint
+	if v := strings.TrimSpace(c.PostForm("job_id")); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid job_id"})
+			return
+		}
+		var job domain.Job
+		if err := h.DB.First(&job, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+			return
+		}
+		jobID = &job.ID
+	}
+
+	header, err := c.FormFile("archive")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "archive is required"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open archive"})
+		return
+	}
+	defer file.Close()
+
+	// Pass pertama hanya mendaftar isi archive (dan membaca manifest.csv) sambil mengecek
+	// batasnya; isi CV / project baru dibaca per pasangan saat upload dibuat
+	limits := h.Limits.BatchArchiveLimits()
+	var files []infrastructure.ArchiveFile
+	err = infrastructure.WalkArchive(file, header.Size, limits, func(p string, r io.Reader) error {
+		f := infrastructure.ArchiveFile{Path: p}
+		if strings.EqualFold(path.Base(p), manifestFilename) {
+			data, err := io.ReadAll(r)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", p, err)
+			}
+			f.Data = data
+		}
+		files = append(files, f)
+		return nil
+	})
+	if err != nil {
+		status := http.StatusUnprocessableEntity
+		switch {
+		case errors.Is(err, infrastructure.ErrArchiveTooLarge):
+			status = http.StatusRequestEntityTooLarge
+		case errors.Is(err, infrastructure.ErrUnsupportedArchive):
+			status = http.StatusUnsupportedMediaType
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	pairs, err := pairBatchFiles(files)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(pairs) == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "archive does not contain any CV / project files"})
+		return
+	}
+
+	batch := domain.UploadBatch{
+		JobID:     jobID,
+		Filename:  path.Base(header.Filename),
+		ItemCount: len(pairs),
+	}
+	if err := h.DB.Create(&batch).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create batch"})
+		return
+	}
+
+	items := h.createBatchItems(c, &batch, pairs, file, header.Size, limits)
+	for i := range items {
+		if err := h.DB.Create(&items[i]).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save batch item"})
+			return
+		}
+		if items[i].Status == "accepted" {
+			batch.AcceptedCount++
+		}
+	}
+	h.DB.Model(&batch).Update("accepted_count", batch.AcceptedCount)
+
+	resp, err := h.batchJSON(batch, items)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load batch status"})
+		return
+	}
+	c.JSON(http.StatusAccepted, resp)
+}
+
+// batchDocument adalah hasil ReadUpload satu file archive yang pasangannya belum lengkap
+type batchDocument struct {
+	doc *infrastructure.UploadedDocument
+	err error
+}
+
+// createBatchItems membaca archive sekali lagi secara berurutan dan membuat upload setiap
+// pasangan begitu kedua file-nya terbaca. Yang ditahan di memory hanya file yang pasangannya
+// belum lengkap (CV dan project satu kandidat biasanya berdekatan di archive). Error per
+// pasangan tidak menggagalkan batch, hanya dicatat di item sebagai rejected.
+func (h *HTTPHandler) createBatchItems(c *gin.Context, batch *domain.UploadBatch, pairs []batchPair, archive io.ReaderAt, size int64, limits infrastructure.ArchiveLimits) []domain.UploadBatchItem {
+	items := make([]domain.UploadBatchItem, len(pairs))
+	needed := map[string][]int{} // path → index pasangan yang memakai file itu
+	for i, pair := range pairs {
+		items[i] = domain.UploadBatchItem{
+			BatchID:         batch.ID,
+			Name:            pair.Name,
+			CVFilename:      pair.CVPath,
+			ProjectFilename: pair.ProjectPath,
+			CandidateName:   pair.CandidateName,
+			CandidateEmail:  pair.CandidateEmail,
+			Status:          "rejected",
+			Error:           pair.Error,
+		}
+		if pair.Error == "" && pair.CVPath == pair.ProjectPath {
+			items[i].Error = "cv_file and project_file must be different files"
+		}
+		if items[i].Error == "" {
+			needed[pair.CVPath] = append(needed[pair.CVPath], i)
+			needed[pair.ProjectPath] = append(needed[pair.ProjectPath], i)
+		}
+	}
+
+	aiClient := infrastructure.NewGeminiClient()
+	pending := map[string]batchDocument{}
+	remaining := map[string]int{}
+	for p, idx := range needed {
+		remaining[p] = len(idx)
+	}
+
+	err := infrastructure.WalkArchive(archive, size, limits, func(p string, r io.Reader) error {
+		idx, ok := needed[p]
+		if !ok {
+			return nil
+		}
+		doc, err := aiClient.ReadUpload(r, path.Base(p), h.Limits.MaxFileSize)
+		pending[p] = batchDocument{doc: doc, err: err}
+
+		for _, i := range idx {
+			cv, cvOK := pending[pairs[i].CVPath]
+			project, projectOK := pending[pairs[i].ProjectPath]
+			if !cvOK || !projectOK {
+				continue
+			}
+			items[i] = h.createBatchItem(c, batch, items[i], cv, project)
+			for _, q := range []string{pairs[i].CVPath, pairs[i].ProjectPath} {
+				if remaining[q]--; remaining[q] == 0 {
+					delete(pending, q)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		for i := range items {
+			if items[i].Status == "rejected" && items[i].Error == "" {
+				items[i].Error = "failed to read archive: " + err.Error()
+			}
+		}
+	}
+	return items
+}
+
+// createBatchItem memvalidasi kedua file satu pasangan dan membuat upload-nya
+func (h *HTTPHandler) createBatchItem(c *gin.Context, batch *domain.UploadBatch, item domain.UploadBatchItem, cv, project batchDocument) domain.UploadBatchItem {
+	if cv.err != nil {
+		item.Error = "cv: " + cv.err.Error()
+		return item
+	}
+	if infrastructure.IsSourceArchive(cv.doc.MIMEType) {
+		item.Error = "cv: source archives are only accepted as project file"
+		return item
+	}
+	if project.err != nil {
+		item.Error = "project: " + project.err.Error()
+		return item
+	}
+
+	upload, _, err := h.createUpload(c.Request.Context(), item.CandidateName, item.CandidateEmail, cv.doc, project.doc, &batch.ID)
+	if upload.ID != 0 {
+		item.UploadID = &upload.ID
+	}
+	if err != nil {
+		item.Error = err.Error()
+		return item
+	}
+
+	item.Status = "accepted"
+	return item
+}
+
+// GetBatch → status per pasangan: status ekstraksi upload dan (kalau batch punya job) status evaluasinya
+func (h *HTTPHandler) GetBatch(c *gin.Context) {
+	id, err := strconv.Atoi(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var batch domain.UploadBatch
+	if err := h.DB.First(&batch, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "batch not found"})
+		return
+	}
+
+	var items []domain.UploadBatchItem
+	if err := h.DB.Where("batch_id = ?", batch.ID).Order("id").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load batch items"})
+		return
+	}
+
+	resp, err := h.batchJSON(batch, items)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load batch status"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// batchJSON menggabungkan item batch dengan status terkini upload dan evaluasinya
+func (h *HTTPHandler) batchJSON(batch domain.UploadBatch, items []domain.UploadBatchItem) (gin.H, error) {
+	var uploadIDs []uint
+	for _, item := range items {
+		if item.UploadID != nil {
+			uploadIDs = append(uploadIDs, *item.UploadID)
+		}
+	}
+
+	uploads := map[uint]domain.Upload{}
+	evaluations := map[uint]domain.Evaluation{}
+	if len(uploadIDs) > 0 {
+		var rows []domain.Upload
+		if err := h.DB.Select("id", "candidate_id", "status", "extraction_error").Where("id IN ?", uploadIDs).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, u := range rows {
+			uploads[u.ID] = u
+		}
+
+		if batch.JobID != nil {
+			var evals []domain.Evaluation
+			if err := h.DB.Select("id", "upload_id", "status").
+				Where("job_id = ? AND upload_id IN ?", *batch.JobID, uploadIDs).
+				Order("id").Find(&evals).Error; err != nil {
+				return nil, err
+			}
+			for _, e := range evals {
+				evaluations[e.UploadID] = e // yang terakhir menang
+			}
+		}
+	}
+
+	summary := map[string]int{}
+	data := make([]gin.H, 0, len(items))
+	for _, item := range items {
+		entry := gin.H{
+			"name":            item.Name,
+			"cv_file":         item.CVFilename,
+			"project_file":    item.ProjectFilename,
+			"candidate_name":  item.CandidateName,
+			"candidate_email": item.CandidateEmail,
+			"status":          item.Status,
+			"upload_id":       item.UploadID,
+		}
+		if item.Error != "" {
+			entry["error"] = item.Error
+		}
+		summary[item.Status]++
+
+		if item.UploadID != nil {
+			if u, ok := uploads[*item.UploadID]; ok {
+				entry["candidate_id"] = u.CandidateID
+				entry["upload_status"] = u.Status
+				if u.ExtractionError != "" {
+					entry["extraction_error"] = u.ExtractionError
+				}
+				summary["upload_"+u.Status]++
+			}
+			if e, ok := evaluations[*item.UploadID]; ok {
+				entry["evaluation_id"] = e.ID
+				entry["evaluation_status"] = e.Status
+				summary["evaluation_"+e.Status]++
+			}
+		}
+		data = append(data, entry)
+	}
+
+	return gin.H{
+		"batch_id":       batch.ID,
+		"job_id":         batch.JobID,
+		"filename":       batch.Filename,
+		"item_count":     batch.ItemCount,
+		"accepted_count": batch.AcceptedCount,
+		"summary":        summary,
+		"status_url":     fmt.Sprintf("/batches/%d", batch.ID),
+		"items":          data,
+		"created_at":     batch.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,234 @@
+package interfaces
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"cv-evaluator/infrastructure"
+)
+
+const manifestFilename = "manifest.csv"
+
+// batchPair adalah satu pasangan CV + project di archive bulk upload. Kalau Error diisi,
+// pasangan ini tidak diproses dan dilaporkan sebagai rejected.
+type batchPair struct {
+	Name           string
+	CVPath         string
+	ProjectPath    string
+	CandidateName  string
+	CandidateEmail string
+	Error          string
+}
+
+// Kolom manifest (case-insensitive) beserta alias yang diterima
+var manifestColumns = map[string][]string{
+	"cv_file":         {"cv_file", "cv", "resume"},
+	"project_file":    {"project_file", "project"},
+	"candidate_name":  {"candidate_name", "name"},
+	"candidate_email": {"candidate_email", "email"},
+}
+
+var (
+	cvNamePattern      = regexp.MustCompile(`(?i)(^|[ _.\-])(cv|resume|curriculum[ _\-]?vitae)$`)
+	projectNamePattern = regexp.MustCompile(`(?i)(^|[ _.\-])(project|report|deliverable|case[ _\-]?study)$`)
+)
+
+// pairBatchFiles memasangkan file di archive jadi pasangan CV + project. Kalau ada
+// manifest.csv, pasangan diambil dari manifest; kalau tidak, dari nama file / folder:
+// "<nama>_cv.pdf" + "<nama>_project.pdf", atau folder per kandidat berisi "cv.*" + "project.*".
+func pairBatchFiles(files []infrastructure.ArchiveFile) ([]batchPair, error) {
+	byPath := map[string]infrastructure.ArchiveFile{}
+	var manifest *infrastructure.ArchiveFile
+	for i, f := range files {
+		byPath[f.Path] = f
+		if strings.EqualFold(path.Base(f.Path), manifestFilename) {
+			if manifest == nil || strings.Count(f.Path, "/") < strings.Count(manifest.Path, "/") {
+				manifest = &files[i]
+			}
+		}
+	}
+
+	if manifest != nil {
+		return pairFromManifest(*manifest, byPath)
+	}
+	return pairByConvention(files), nil
+}
+
+func pairFromManifest(manifest infrastructure.ArchiveFile, byPath map[string]infrastructure.ArchiveFile) ([]batchPair, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(manifest.Data, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", manifestFilename, err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for col, aliases := range manifestColumns {
+			for _, alias := range aliases {
+				if name == alias {
+					columns[col] = i
+				}
+			}
+		}
+	}
+	if _, ok := columns["cv_file"]; !ok {
+		return nil, fmt.Errorf("invalid %s: missing cv_file column", manifestFilename)
+	}
+	if _, ok := columns["project_file"]; !ok {
+		return nil, fmt.Errorf("invalid %s: missing project_file column", manifestFilename)
+	}
+
+	field := func(record []string, col string) string {
+		i, ok := columns[col]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	// Path di manifest relatif terhadap folder tempat manifest berada
+	dir := path.Dir(manifest.Path)
+	resolve := func(p string) string {
+		if p == "" {
+			return ""
+		}
+		return path.Clean(path.Join(dir, strings.ReplaceAll(p, "\\", "/")))
+	}
+
+	var pairs []batchPair
+	for row := 2; ; row++ {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s at row %d: %w", manifestFilename, row, err)
+		}
+
+		pair := batchPair{
+			Name:           fmt.Sprintf("row %d", row),
+			CVPath:         resolve(field(record, "cv_file")),
+			ProjectPath:    resolve(field(record, "project_file")),
+			CandidateName:  field(record, "candidate_name"),
+			CandidateEmail: field(record, "candidate_email"),
+		}
+		if pair.CandidateName != "" {
+			pair.Name = pair.CandidateName
+		}
+
+		switch {
+		case pair.CVPath == "" || pair.ProjectPath == "":
+			pair.Error = "cv_file and project_file are required"
+		case !fileExists(byPath, pair.CVPath):
+			pair.Error = "cv_file not found in archive: " + pair.CVPath
+		case !fileExists(byPath, pair.ProjectPath):
+			pair.Error = "project_file not found in archive: " + pair.ProjectPath
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs, nil
+}
+
+func fileExists(byPath map[string]infrastructure.ArchiveFile, p string) bool {
+	_, ok := byPath[p]
+	return ok
+}
+
+func pairByConvention(files []infrastructure.ArchiveFile) []batchPair {
+	type group struct {
+		cv, project []string
+	}
+	groups := map[string]*group{}
+	var unknown []string
+
+	for _, f := range files {
+		dir := path.Dir(f.Path)
+		base := strings.TrimSuffix(path.Base(f.Path), path.Ext(f.Path))
+
+		var role string
+		var loc []int
+		if loc = cvNamePattern.FindStringIndex(base); loc != nil {
+			role = "cv"
+		} else if loc = projectNamePattern.FindStringIndex(base); loc != nil {
+			role = "project"
+		} else {
+			unknown = append(unknown, f.Path)
+			continue
+		}
+
+		// Key pasangan = folder + nama file tanpa akhiran _cv / _project
+		key := dir
+		if prefix := strings.Trim(base[:loc[0]], " _.-"); prefix != "" {
+			key = path.Join(dir, prefix)
+		}
+		if groups[key] == nil {
+			groups[key] = &group{}
+		}
+		if role == "cv" {
+			groups[key].cv = append(groups[key].cv, f.Path)
+		} else {
+			groups[key].project = append(groups[key].project, f.Path)
+		}
+	}
+
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var pairs []batchPair
+	for _, key := range keys {
+		g := groups[key]
+		pair := batchPair{Name: key, CandidateName: candidateNameFromKey(key)}
+		if len(g.cv) == 1 {
+			pair.CVPath = g.cv[0]
+		}
+		if len(g.project) == 1 {
+			pair.ProjectPath = g.project[0]
+		}
+
+		switch {
+		case key == ".":
+			pair.Error = "files at the archive root must be named <candidate>_cv / <candidate>_project"
+		case len(g.cv) > 1 || len(g.project) > 1:
+			pair.Error = fmt.Sprintf("ambiguous pair: %d CV files and %d project files", len(g.cv), len(g.project))
+		case len(g.cv) == 0:
+			pair.Error = "no matching CV file"
+		case len(g.project) == 0:
+			pair.Error = "no matching project file"
+		}
+		pairs = append(pairs, pair)
+	}
+
+	for _, p := range unknown {
+		pairs = append(pairs, batchPair{
+			Name:  p,
+			Error: "cannot tell whether file is a CV or a project (name it <candidate>_cv / <candidate>_project or add " + manifestFilename + ")",
+		})
+	}
+	return pairs
+}
+
+// candidateNameFromKey: "batch-2024/john_doe" → "John Doe"
+func candidateNameFromKey(key string) string {
+	name := strings.NewReplacer("_", " ", "-", " ", ".", " ").Replace(path.Base(key))
+	words := strings.Fields(name)
+	for i, w := range words {
+		r, size := utf8.DecodeRuneInString(w)
+		words[i] = string(unicode.ToUpper(r)) + w[size:]
+	}
+	return strings.Join(words, " ")
+}
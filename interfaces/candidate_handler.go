@@ -0,0 +1,150 @@
+package interfaces
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"cv-evaluator/domain"
+)
+
+// ListCandidates → daftar kandidat, bisa dicari berdasarkan email (?email=) dan hasil
+// parsing CV (?skill=, ?company=, ?institution=, ?language=, ?certification=, ?min_years=)
+func (h *HTTPHandler) ListCandidates(c *gin.Context) {
+	query := h.DB.Model(&domain.Candidate{}).Order("id DESC").Limit(maxListLimit)
+	if email := domain.NormalizeEmail(c.Query("email")); email != "" {
+		query = query.Where("email = ?", email)
+	}
+	query, err := applyCVProfileFilters(h.DB, c, query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var candidates []domain.Candidate
+	if err := query.Find(&candidates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list candidates"})
+		return
+	}
+
+	items := make([]gin.H, 0, len(candidates))
+	for _, cand := range candidates {
+		items = append(items, candidateJSON(cand))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": items})
+}
+
+// GetCandidate ambil data kandidat beserta jumlah upload dan evaluasi
+func (h *HTTPHandler) GetCandidate(c *gin.Context) {
+	candidate, ok := h.loadCandidate(c)
+	if !ok {
+		return
+	}
+
+	var uploadCount, evaluationCount int64
+	h.DB.Model(&domain.Upload{}).Where("candidate_id = ?", candidate.ID).Count(&uploadCount)
+	h.DB.Model(&domain.Evaluation{}).
+		Joins("JOIN uploads ON uploads.id = evaluations.upload_id").
+		Where("uploads.candidate_id = ?", candidate.ID).
+		Count(&evaluationCount)
+
+	resp := candidateJSON(candidate)
+	resp["upload_count"] = uploadCount
+	resp["evaluation_count"] = evaluationCount
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListCandidateUploads → semua upload milik kandidat (tanpa teks lengkap)
+func (h *HTTPHandler) ListCandidateUploads(c *gin.Context) {
+	candidate, ok := h.loadCandidate(c)
+	if !ok {
+		return
+	}
+
+	var uploads []domain.Upload
+	if err := h.DB.
+		Where("candidate_id = ?", candidate.ID).
+		Order("id DESC").
+		Find(&uploads).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list uploads"})
+		return
+	}
+
+	items := make([]gin.H, 0, len(uploads))
+	for _, u := range uploads {
+		items = append(items, gin.H{
+			"id":                  u.ID,
+			"status":              u.Status,
+			"cv_text_length":      len(u.CVText),
+			"project_text_length": len(u.ProjectText),
+			"created_at":          u.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"candidate_id": candidate.ID, "data": items})
+}
+
+// ListCandidateEvaluations → semua evaluasi kandidat di semua job
+func (h *HTTPHandler) ListCandidateEvaluations(c *gin.Context) {
+	candidate, ok := h.loadCandidate(c)
+	if !ok {
+		return
+	}
+
+	var rows []struct {
+		domain.Evaluation
+		JobTitle string
+	}
+	if err := h.DB.Model(&domain.Evaluation{}).
+		Select("evaluations.*, jobs.title AS job_title").
+		Joins("JOIN uploads ON uploads.id = evaluations.upload_id").
+		Joins("JOIN jobs ON jobs.id = evaluations.job_id").
+		Where("uploads.candidate_id = ?", candidate.ID).
+		Order("evaluations.id DESC").
+		Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list evaluations"})
+		return
+	}
+
+	items := make([]gin.H, 0, len(rows))
+	for _, r := range rows {
+		items = append(items, gin.H{
+			"id":              r.ID,
+			"upload_id":       r.UploadID,
+			"job_id":          r.JobID,
+			"job_title":       r.JobTitle,
+			"status":          r.Status,
+			"cv_match_rate":   r.CVMatchRate,
+			"project_score":   r.ProjectScore,
+			"overall_summary": r.OverallSummary,
+			"created_at":      r.CreatedAt,
+			"updated_at":      r.UpdatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"candidate_id": candidate.ID, "data": items})
+}
+
+func (h *HTTPHandler) loadCandidate(c *gin.Context) (domain.Candidate, bool) {
+	var candidate domain.Candidate
+	id, err := strconv.Atoi(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return candidate, false
+	}
+	if err := h.DB.First(&candidate, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "candidate not found"})
+		return candidate, false
+	}
+	return candidate, true
+}
+
+func candidateJSON(cand domain.Candidate) gin.H {
+	return gin.H{
+		"id":         cand.ID,
+		"name":       cand.Name,
+		"email":      cand.EmailOrEmpty(),
+		"created_at": cand.CreatedAt,
+		"updated_at": cand.UpdatedAt,
+	}
+}
@@ -0,0 +1,316 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"cv-evaluator/domain"
+	"cv-evaluator/infrastructure"
+)
+
+// parseUploadCV menjalankan parsing CV terstruktur setelah teks upload siap. Parsing yang
+// gagal tidak menggagalkan upload, hanya dicatat sebagai cv_profile dengan status failed.
+// PII di teks CV diredaksi sebelum dikirim ke model (kecuali PII_REDACTION=off).
+func parseUploadCV(db *gorm.DB, gemini *infrastructure.GeminiClient, upload domain.Upload) (domain.CVProfile, error) {
+	cvText, entries, err := infrastructure.RedactCVForParsing(db, upload)
+	var parsed *infrastructure.ParsedCV
+	if err == nil {
+		parsed, err = gemini.ParseCV(context.Background(), cvText)
+	}
+	if err == nil {
+		var profile domain.CVProfile
+		if profile, err = infrastructure.SaveCVProfile(db, upload.ID, parsed, entries); err == nil {
+			// Timeline pengalaman dihitung di Go dari tanggal posisi, bukan ditebak model
+			if _, err := infrastructure.SaveExperienceTimeline(db, upload.ID, time.Now()); err != nil {
+				log.Printf("❌ Experience timeline for upload %d failed: %v", upload.ID, err)
+			}
+			return profile, nil
+		}
+	}
+
+	log.Printf("❌ CV parsing for upload %d failed: %v", upload.ID, err)
+	if saveErr := infrastructure.SaveCVProfileFailure(db, upload.ID, err); saveErr != nil {
+		log.Printf("❌ Failed to record CV parsing failure for upload %d: %v", upload.ID, saveErr)
+	}
+	return domain.CVProfile{}, err
+}
+
+// GetCVProfile → hasil parsing CV terstruktur sebuah upload
+func (h *HTTPHandler) GetCVProfile(c *gin.Context) {
+	upload, ok := h.loadUpload(c)
+	if !ok {
+		return
+	}
+
+	var profile domain.CVProfile
+	if err := h.DB.Where("upload_id = ?", upload.ID).First(&profile).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "cv profile not found", "upload_status": upload.Status})
+		return
+	}
+
+	resp, err := h.cvProfileJSON(profile)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load cv profile"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ReparseCVProfile → jalankan ulang parsing CV (mis. setelah gagal), hasilnya langsung dikembalikan
+func (h *HTTPHandler) ReparseCVProfile(c *gin.Context) {
+	upload, ok := h.loadUpload(c)
+	if !ok {
+		return
+	}
+	if upload.Status != "ready" {
+		c.JSON(http.StatusConflict, gin.H{"error": "upload text is not ready", "status": upload.Status})
+		return
+	}
+
+	profile, err := parseUploadCV(h.DB, infrastructure.NewGeminiClient(), upload)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "cv parsing failed: " + err.Error()})
+		return
+	}
+
+	resp, err := h.cvProfileJSON(profile)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load cv profile"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *HTTPHandler) loadUpload(c *gin.Context) (domain.Upload, bool) {
+	var upload domain.Upload
+	id, err := strconv.Atoi(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return upload, false
+	}
+	if err := h.DB.First(&upload, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "upload not found"})
+		return upload, false
+	}
+	return upload, true
+}
+
+func (h *HTTPHandler) cvProfileJSON(profile domain.CVProfile) (gin.H, error) {
+	resp := gin.H{
+		"upload_id":  profile.UploadID,
+		"status":     profile.Status,
+		"created_at": profile.CreatedAt,
+		"updated_at": profile.UpdatedAt,
+	}
+	if profile.Status != "parsed" {
+		resp["error"] = profile.Error
+		return resp, nil
+	}
+
+	var positions []domain.CVPosition
+	var education []domain.CVEducation
+	var skills []domain.CVSkill
+	var certifications []domain.CVCertification
+	var languages []domain.CVLanguage
+	for _, q := range []struct {
+		dest  interface{}
+		order string
+	}{
+		{&positions, "sort_order, id"},
+		{&education, "sort_order, id"},
+		{&skills, "id"},
+		{&certifications, "id"},
+		{&languages, "id"},
+	} {
+		if err := h.DB.Where("upload_id = ?", profile.UploadID).Order(q.order).Find(q.dest).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	positionItems := make([]gin.H, 0, len(positions))
+	for _, p := range positions {
+		positionItems = append(positionItems, gin.H{
+			"title":       p.Title,
+			"company":     p.Company,
+			"location":    p.Location,
+			"start_date":  cvDate(p.StartDate),
+			"end_date":    cvDate(p.EndDate),
+			"current":     p.Current,
+			"description": p.Description,
+		})
+	}
+	educationItems := make([]gin.H, 0, len(education))
+	for _, e := range education {
+		educationItems = append(educationItems, gin.H{
+			"institution": e.Institution,
+			"degree":      e.Degree,
+			"field":       e.Field,
+			"start_date":  cvDate(e.StartDate),
+			"end_date":    cvDate(e.EndDate),
+			"grade":       e.Grade,
+		})
+	}
+	skillItems := make([]gin.H, 0, len(skills))
+	for _, s := range skills {
+		skillItems = append(skillItems, gin.H{"name": s.Name, "category": s.Category})
+	}
+	certificationItems := make([]gin.H, 0, len(certifications))
+	for _, cert := range certifications {
+		certificationItems = append(certificationItems, gin.H{
+			"name":        cert.Name,
+			"issuer":      cert.Issuer,
+			"issued_date": cvDate(cert.IssuedAt),
+			"expiry_date": cvDate(cert.ExpiresAt),
+		})
+	}
+	languageItems := make([]gin.H, 0, len(languages))
+	for _, l := range languages {
+		languageItems = append(languageItems, gin.H{"language": l.Language, "proficiency": l.Proficiency})
+	}
+
+	resp["contact"] = gin.H{
+		"name":     profile.FullName,
+		"email":    profile.Email,
+		"phone":    profile.Phone,
+		"location": profile.Location,
+		"headline": profile.Headline,
+	}
+	resp["summary"] = profile.Summary
+	resp["positions"] = positionItems
+	resp["education"] = educationItems
+	resp["skills"] = skillItems
+	resp["certifications"] = certificationItems
+	resp["languages"] = languageItems
+
+	experience, err := h.experienceJSON(profile.UploadID)
+	if err != nil {
+		return nil, err
+	}
+	resp["experience"] = experience
+	return resp, nil
+}
+
+// experienceJSON → timeline pengalaman hasil analisis deterministik (null kalau belum ada)
+func (h *HTTPHandler) experienceJSON(uploadID uint) (gin.H, error) {
+	var timeline domain.ExperienceTimeline
+	err := h.DB.Where("upload_id = ?", uploadID).First(&timeline).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var skills []domain.SkillExperience
+	if err := h.DB.Where("upload_id = ?", uploadID).Order("months DESC, id").Find(&skills).Error; err != nil {
+		return nil, err
+	}
+
+	// Posisi yang masih berjalan dihitung sampai hari ini, bukan sampai CV di-parse
+	now := time.Now()
+	timeline = timeline.At(now)
+	for i := range skills {
+		skills[i] = skills[i].At(timeline, now)
+	}
+	sort.SliceStable(skills, func(i, j int) bool { return skills[i].Months > skills[j].Months })
+
+	skillItems := make([]gin.H, 0, len(skills))
+	for _, s := range skills {
+		skillItems = append(skillItems, gin.H{
+			"skill":     s.Name,
+			"months":    s.Months,
+			"years":     domain.Years(s.Months),
+			"positions": s.Positions,
+		})
+	}
+
+	return gin.H{
+		"total_months":   timeline.TotalMonths,
+		"total_years":    domain.Years(timeline.TotalMonths),
+		"position_count": timeline.PositionCount,
+		"first_start":    cvDate(timeline.FirstStart),
+		"last_end":       cvDate(timeline.LastEnd),
+		"current":        timeline.Current,
+		"skills":         skillItems,
+		"gaps":           rawJSON(timeline.Gaps),
+		"overlaps":       rawJSON(timeline.Overlaps),
+		"issues":         rawJSON(timeline.Issues),
+		"analyzed_at":    timeline.AnalyzedAt,
+	}, nil
+}
+
+// cvDate: tanggal di CV hanya sampai bulan → "2021-03"
+func cvDate(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Format("2006-01")
+}
+
+// applyCVProfileFilters memfilter kandidat dari hasil parsing CV di salah satu upload-nya:
+// ?skill= (nama atau alias taxonomy, boleh berulang, semua harus ada), ?company=, ?institution=, ?language=,
+// ?certification= (sebagian nama, case-insensitive) dan ?min_years= (total pengalaman)
+func applyCVProfileFilters(db *gorm.DB, c *gin.Context, query *gorm.DB) (*gorm.DB, error) {
+	// candidates.id IN (uploads milik kandidat yang punya record cocok)
+	withRecord := func(model interface{}, cond string, args ...interface{}) *gorm.DB {
+		uploads := db.Model(model).Select("upload_id").Where(cond, args...)
+		return query.Where("id IN (?)", db.Model(&domain.Upload{}).Select("candidate_id").Where("id IN (?)", uploads))
+	}
+
+	if skills := c.QueryArray("skill"); len(skills) > 0 {
+		// Alias dinormalisasi seperti skill CV: ?skill=golang cocok dengan kandidat yang menulis "Go"
+		taxonomy, err := infrastructure.LoadSkillTaxonomy(db)
+		if err != nil {
+			return nil, err
+		}
+		for _, skill := range skills {
+			if s := domain.NormalizeSkill(taxonomy.Canonical(skill)); s != "" {
+				query = withRecord(&domain.CVSkill{}, "normalized_name = ?", s)
+			}
+		}
+	}
+	partial := []struct {
+		param  string
+		model  interface{}
+		column string
+	}{
+		{"company", &domain.CVPosition{}, "company"},
+		{"institution", &domain.CVEducation{}, "institution"},
+		{"language", &domain.CVLanguage{}, "language"},
+		{"certification", &domain.CVCertification{}, "name"},
+	}
+	for _, f := range partial {
+		if v := strings.TrimSpace(c.Query(f.param)); v != "" {
+			query = withRecord(f.model, f.column+" LIKE ?", "%"+escapeLike(v)+"%")
+		}
+	}
+	if v := strings.TrimSpace(c.Query("min_years")); v != "" {
+		years, err := strconv.ParseFloat(v, 64)
+		if err != nil || years < 0 || math.IsNaN(years) || math.IsInf(years, 0) || years > 100 {
+			return nil, errors.New("invalid min_years")
+		}
+		// total_months tersimpan per bulan analisis; kandidat yang masih bekerja ditambah bulan
+		// sejak last_end (= bulan analisis), sama seperti ExperienceTimeline.At
+		now := time.Now()
+		thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		query = withRecord(&domain.ExperienceTimeline{},
+			"total_months + CASE WHEN `current` THEN GREATEST(TIMESTAMPDIFF(MONTH, last_end, ?), 0) ELSE 0 END >= ?",
+			thisMonth, int(math.Ceil(years*12)))
+	}
+	return query, nil
+}
+
+// escapeLike supaya % dan _ dari input user tidak jadi wildcard
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,297 @@
+package interfaces
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"cv-evaluator/domain"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+// Kolom yang boleh dipakai untuk sorting di GET /evaluations
+var evaluationSortColumns = map[string]string{
+	"created_at":    "created_at",
+	"cv_match_rate": "cv_match_rate",
+	"project_score": "project_score",
+}
+
+// Field yang bisa dipilih lewat ?fields=
+var evaluationFields = map[string]func(e domain.Evaluation) interface{}{
+	"id":                 func(e domain.Evaluation) interface{} { return e.ID },
+	"upload_id":          func(e domain.Evaluation) interface{} { return e.UploadID },
+	"job_id":             func(e domain.Evaluation) interface{} { return e.JobID },
+	"status":             func(e domain.Evaluation) interface{} { return e.Status },
+	"cv_match_rate":      func(e domain.Evaluation) interface{} { return e.CVMatchRate },
+	"cv_feedback":        func(e domain.Evaluation) interface{} { return e.CVFeedback },
+	"project_score":      func(e domain.Evaluation) interface{} { return e.ProjectScore },
+	"project_feedback":   func(e domain.Evaluation) interface{} { return e.ProjectFeedback },
+	"overall_summary":    func(e domain.Evaluation) interface{} { return e.OverallSummary },
+	"rejected_rule_id":   func(e domain.Evaluation) interface{} { return e.RejectedRuleID },
+	"knockout_result":    func(e domain.Evaluation) interface{} { return rawJSONPtr(e.KnockoutResult) },
+	"redaction_mode":     func(e domain.Evaluation) interface{} { return e.RedactionMode },
+	"needs_review":       func(e domain.Evaluation) interface{} { return e.NeedsReview },
+	"injection_spans":    func(e domain.Evaluation) interface{} { return rawJSONPtr(e.InjectionSpans) },
+	"prompt_report":      func(e domain.Evaluation) interface{} { return rawJSONPtr(e.PromptReport) },
+	"prompt_template_id": func(e domain.Evaluation) interface{} { return e.PromptTemplateID },
+	"completed_at":       func(e domain.Evaluation) interface{} { return e.CompletedAt },
+	"created_at":         func(e domain.Evaluation) interface{} { return e.CreatedAt },
+	"updated_at":         func(e domain.Evaluation) interface{} { return e.UpdatedAt },
+}
+
+var defaultEvaluationFields = []string{
+	"id", "upload_id", "job_id", "status",
+	"cv_match_rate", "cv_feedback", "project_score", "project_feedback", "overall_summary",
+	"created_at", "updated_at",
+}
+
+// listCursor menyimpan posisi terakhir halaman sebelumnya (nilai kolom sort + id)
+type listCursor struct {
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+func encodeCursor(c listCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (listCursor, error) {
+	var c listCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(b, &c)
+	return c, err
+}
+
+// ListEvaluations → daftar evaluasi dengan filter, sorting dan cursor pagination
+func (h *HTTPHandler) ListEvaluations(c *gin.Context) {
+	query := h.DB.Model(&domain.Evaluation{})
+
+	query, err := applyEvaluationFilters(c, query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sortKey := c.DefaultQuery("sort", "created_at")
+	sortColumn, ok := evaluationSortColumns[sortKey]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sort: " + sortKey})
+		return
+	}
+
+	order := strings.ToLower(c.DefaultQuery("order", "desc"))
+	if order != "asc" && order != "desc" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order must be asc or desc"})
+		return
+	}
+
+	limit := defaultListLimit
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		if n > maxListLimit {
+			n = maxListLimit
+		}
+		limit = n
+	}
+
+	fields, err := parseFields(c.Query("fields"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Keyset pagination: lanjut setelah (nilai sort, id) dari cursor
+	if raw := c.Query("cursor"); raw != "" {
+		cur, err := decodeCursor(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+			return
+		}
+
+		var value interface{} = cur.Value
+		if sortColumn == "created_at" {
+			t, err := time.Parse(time.RFC3339Nano, cur.Value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cursor"})
+				return
+			}
+			value = t
+		}
+
+		op := "<"
+		if order == "asc" {
+			op = ">"
+		}
+		query = query.Where(
+			fmt.Sprintf("(%s %s ?) OR (%s = ? AND id %s ?)", sortColumn, op, sortColumn, op),
+			value, value, cur.ID,
+		)
+	}
+
+	var evals []domain.Evaluation
+	if err := query.
+		Order(sortColumn + " " + order).
+		Order("id " + order).
+		Limit(limit + 1).
+		Find(&evals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list evaluations"})
+		return
+	}
+
+	// Ambil satu baris ekstra untuk tahu masih ada halaman berikutnya atau tidak
+	var nextCursor string
+	if len(evals) > limit {
+		evals = evals[:limit]
+		last := evals[len(evals)-1]
+		nextCursor = encodeCursor(listCursor{Value: sortValue(last, sortColumn), ID: last.ID})
+	}
+
+	items := make([]gin.H, 0, len(evals))
+	for _, e := range evals {
+		item := gin.H{}
+		for _, f := range fields {
+			item[f] = evaluationFields[f](e)
+		}
+		items = append(items, item)
+	}
+
+	resp := gin.H{"data": items}
+	if nextCursor != "" {
+		resp["next_cursor"] = nextCursor
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func applyEvaluationFilters(c *gin.Context, query *gorm.DB) (*gorm.DB, error) {
+	uintFilters := map[string]string{"job_id": "job_id", "upload_id": "upload_id"}
+	for param, column := range uintFilters {
+		if v := c.Query(param); v != "" {
+			id, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s", param)
+			}
+			query = query.Where(column+" = ?", id)
+		}
+	}
+
+	if v := c.Query("status"); v != "" {
+		statuses := strings.Split(v, ",")
+		for i, s := range statuses {
+			statuses[i] = strings.TrimSpace(s) // "completed, failed"
+			if !domain.IsValidEvaluationStatus(statuses[i]) {
+				return nil, fmt.Errorf("invalid status: %s", statuses[i])
+			}
+		}
+		query = query.Where("status IN ?", statuses)
+	}
+
+	if v := c.Query("needs_review"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid needs_review")
+		}
+		query = query.Where("needs_review = ?", b)
+	}
+
+	floatFilters := []struct {
+		param string
+		cond  string
+	}{
+		{"min_match_rate", "cv_match_rate >= ?"},
+		{"max_match_rate", "cv_match_rate <= ?"},
+		{"min_project_score", "project_score >= ?"},
+		{"max_project_score", "project_score <= ?"},
+	}
+	for _, f := range floatFilters {
+		if v := c.Query(f.param); v != "" {
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s", f.param)
+			}
+			query = query.Where(f.cond, n)
+		}
+	}
+
+	if v := c.Query("created_from"); v != "" {
+		t, _, err := parseDate(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid created_from (use RFC3339 or YYYY-MM-DD)")
+		}
+		query = query.Where("created_at >= ?", t)
+	}
+	if v := c.Query("created_to"); v != "" {
+		t, dateOnly, err := parseDate(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid created_to (use RFC3339 or YYYY-MM-DD)")
+		}
+		if dateOnly {
+			// created_to=2024-05-31 berarti sampai akhir hari itu, bukan jam 00:00
+			query = query.Where("created_at < ?", t.AddDate(0, 0, 1))
+		} else {
+			query = query.Where("created_at <= ?", t)
+		}
+	}
+
+	return query, nil
+}
+
+// parseDate menerima RFC3339 atau YYYY-MM-DD; dateOnly true untuk format tanggal saja
+func parseDate(v string) (t time.Time, dateOnly bool, err error) {
+	v = strings.TrimSpace(v)
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, false, nil
+	}
+	t, err = time.ParseInLocation("2006-01-02", v, time.Local)
+	return t, true, err
+}
+
+func parseFields(v string) ([]string, error) {
+	if v == "" {
+		return defaultEvaluationFields, nil
+	}
+	var fields []string
+	for _, f := range strings.Split(v, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if _, ok := evaluationFields[f]; !ok {
+			return nil, fmt.Errorf("unknown field: %s", f)
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return defaultEvaluationFields, nil
+	}
+	return fields, nil
+}
+
+func sortValue(e domain.Evaluation, column string) string {
+	switch column {
+	case "cv_match_rate":
+		return strconv.FormatFloat(e.CVMatchRate, 'g', -1, 64)
+	case "project_score":
+		return strconv.FormatFloat(e.ProjectScore, 'g', -1, 64)
+	default:
+		return e.CreatedAt.Format(time.RFC3339Nano)
+	}
+}
@@ -0,0 +1,189 @@
+package interfaces
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+
+	"gorm.io/gorm"
+
+	"cv-evaluator/domain"
+	"cv-evaluator/infrastructure"
+)
+
+// documentLabels dipakai di pesan error, sama seperti label di response upload
+var documentLabels = map[string]string{"cv": "CV", "project": "Project"}
+
+// NewExtractionWorker membuat handler untuk extraction queue → baca file original dari blob
+// storage, ekstrak teks, lalu simpan teks, metadata dan link. Status upload jadi "ready"
+// setelah parsing CV selesai (berhasil atau gagal), atau "failed" beserta alasannya (mis.
+// kualitas teks di bawah threshold).
+func NewExtractionWorker(db *gorm.DB, gemini *infrastructure.GeminiClient, blobs infrastructure.BlobStore, rmq *infrastructure.RabbitMQ) func(infrastructure.ExtractionJob) {
+	return func(job infrastructure.ExtractionJob) {
+		log.Printf("📥 Worker processing extraction: %+v\n", job)
+
+		var upload domain.Upload
+		if err := db.First(&upload, job.UploadID).Error; err != nil {
+			log.Printf("❌ Failed to load upload %d: %v", job.UploadID, err)
+			return
+		}
+		if upload.Status != "extracting" {
+			log.Printf("⏭️ Upload %d is already %s, skipping extraction", upload.ID, upload.Status)
+			return
+		}
+
+		if err := runExtraction(db, gemini, blobs, &upload); err != nil {
+			log.Printf("❌ Extraction for upload %d failed: %v", upload.ID, err)
+			db.Model(&upload).Updates(map[string]interface{}{
+				"status":           "failed",
+				"extraction_error": err.Error(),
+			})
+			return
+		}
+		log.Printf("✅ Worker finished extraction for upload %d (flagged: %v)\n", upload.ID, upload.ExtractionFlagged)
+
+		// Parsing CV terstruktur (posisi, pendidikan, skill, ...) untuk filter kandidat. Upload
+		// baru "ready" setelah ini, supaya evaluasi tidak jalan tanpa profil / timeline pengalaman.
+		if _, err := parseUploadCV(db, gemini, upload); err == nil {
+			log.Printf("✅ Parsed CV profile for upload %d", upload.ID)
+		}
+		if err := db.Model(&upload).Update("status", "ready").Error; err != nil {
+			log.Printf("❌ Failed to mark upload %d ready: %v", upload.ID, err)
+			return
+		}
+		upload.Status = "ready"
+
+		queueBatchEvaluation(db, rmq, upload)
+	}
+}
+
+// queueBatchEvaluation: upload dari bulk upload yang punya job_id langsung dievaluasi
+// begitu teksnya siap
+func queueBatchEvaluation(db *gorm.DB, rmq *infrastructure.RabbitMQ, upload domain.Upload) {
+	if upload.BatchID == nil {
+		return
+	}
+	var batch domain.UploadBatch
+	if err := db.First(&batch, *upload.BatchID).Error; err != nil || batch.JobID == nil {
+		return
+	}
+
+	eval, err := queueEvaluation(db, rmq, upload.ID, *batch.JobID)
+	if err != nil {
+		log.Printf("❌ Failed to queue evaluation for upload %d (batch %d): %v", upload.ID, batch.ID, err)
+		return
+	}
+	log.Printf("📤 Queued evaluation %d for upload %d (batch %d)", eval.ID, upload.ID, batch.ID)
+}
+
+func runExtraction(db *gorm.DB, gemini *infrastructure.GeminiClient, blobs infrastructure.BlobStore, upload *domain.Upload) error {
+	var files []domain.StoredFile
+	if err := db.Where("upload_id = ?", upload.ID).Order("id").Find(&files).Error; err != nil {
+		return fmt.Errorf("load files: %w", err)
+	}
+
+	quality := gemini.QualityThresholds()
+	results := map[string]*infrastructure.ExtractionResult{}
+	var metadata []domain.ExtractionMetadata
+	var links []domain.DocumentLink
+	flagged := false
+
+	for _, f := range files {
+		label := documentLabels[f.Kind]
+
+		doc, err := loadStoredDocument(blobs, f)
+		if err != nil {
+			return saveFailedExtraction(db, upload.ID, metadata, fmt.Errorf("%s: %w", label, err))
+		}
+
+		result, extractErr := gemini.ExtractText(doc)
+
+		// Provenance ekstraksi per dokumen, di-link ke file original-nya. Dicatat juga untuk
+		// dokumen yang gagal, supaya extractor, halaman gagal dan warning-nya bisa dicek.
+		meta := result.Metadata(f.Kind)
+		meta.UploadID = upload.ID
+		meta.StoredFileID = &f.ID
+		metadata = append(metadata, meta)
+
+		if extractErr != nil {
+			return saveFailedExtraction(db, upload.ID, metadata, fmt.Errorf("failed to extract %s text: %w", label, extractErr))
+		}
+		if err := quality.Acceptable(result); err != nil {
+			return saveFailedExtraction(db, upload.ID, metadata, fmt.Errorf("%s: %w", label, err))
+		}
+		flagged = flagged || quality.Flagged(result)
+		results[f.Kind] = result
+
+		// Link (GitHub, LinkedIn, portfolio, ...) dari annotation PDF dan teks dokumen
+		for _, l := range result.LinkRecords(f.Kind) {
+			l.UploadID = upload.ID
+			links = append(links, l)
+		}
+	}
+	if results["cv"] == nil || results["project"] == nil {
+		return fmt.Errorf("upload is missing its CV or project file")
+	}
+
+	upload.CVText = results["cv"].Text
+	upload.ProjectText = results["project"].Text
+	upload.ExtractionFlagged = flagged
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := replaceExtractionMetadata(tx, upload.ID, metadata); err != nil {
+			return err
+		}
+		if len(links) > 0 {
+			if err := tx.Create(&links).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(upload).Updates(map[string]interface{}{
+			"cv_text":            upload.CVText,
+			"project_text":       upload.ProjectText,
+			"extraction_flagged": upload.ExtractionFlagged,
+			"extraction_error":   "",
+		}).Error
+	})
+}
+
+// saveFailedExtraction menyimpan metadata dokumen yang sudah diproses (termasuk dokumen yang
+// gagal) lalu mengembalikan cause, supaya GET /uploads/:id tetap menunjukkan provenance-nya
+func saveFailedExtraction(db *gorm.DB, uploadID uint, metadata []domain.ExtractionMetadata, cause error) error {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return replaceExtractionMetadata(tx, uploadID, metadata)
+	})
+	if err != nil {
+		log.Printf("❌ Failed to save extraction metadata for upload %d: %v", uploadID, err)
+	}
+	return cause
+}
+
+// replaceExtractionMetadata mengganti hasil ekstraksi sebelumnya (kalau job ini di-retry)
+func replaceExtractionMetadata(tx *gorm.DB, uploadID uint, metadata []domain.ExtractionMetadata) error {
+	if err := tx.Where("upload_id = ?", uploadID).Delete(&domain.ExtractionMetadata{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("upload_id = ?", uploadID).Delete(&domain.DocumentLink{}).Error; err != nil {
+		return err
+	}
+	if len(metadata) == 0 {
+		return nil
+	}
+	return tx.Create(&metadata).Error
+}
+
+// loadStoredDocument membaca file original dari blob storage sebagai dokumen yang siap diekstrak
+func loadStoredDocument(blobs infrastructure.BlobStore, f domain.StoredFile) (*infrastructure.UploadedDocument, error) {
+	body, err := blobs.Get(context.Background(), f.StorageKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stored file: %w", err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stored file: %w", err)
+	}
+	return &infrastructure.UploadedDocument{Filename: f.Filename, MIMEType: f.MimeType, Data: data}, nil
+}
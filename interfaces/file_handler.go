@@ -0,0 +1,182 @@
+package interfaces
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"cv-evaluator/domain"
+	"cv-evaluator/infrastructure"
+)
+
+const (
+	defaultDownloadTTL = 5 * time.Minute
+	maxDownloadTTL     = time.Hour
+)
+
+// storeOriginal menyimpan file original ke blob storage beserta checksum, size dan MIME type.
+// UploadID diisi oleh pemanggil setelah upload tersimpan di DB.
+func (h *HTTPHandler) storeOriginal(ctx context.Context, doc *infrastructure.UploadedDocument, kind string) (domain.StoredFile, error) {
+	key := fmt.Sprintf("originals/%s/%s/%s%s",
+		time.Now().Format("2006/01/02"),
+		randomHex(16),
+		kind,
+		strings.ToLower(filepath.Ext(doc.Filename)),
+	)
+
+	size := int64(len(doc.Data))
+	if err := h.Blobs.Put(ctx, key, bytes.NewReader(doc.Data), size, doc.MIMEType); err != nil {
+		return domain.StoredFile{}, err
+	}
+
+	sum := sha256.Sum256(doc.Data)
+	return domain.StoredFile{
+		Kind:       kind,
+		Filename:   filepath.Base(doc.Filename),
+		MimeType:   doc.MIMEType,
+		Size:       size,
+		SHA256:     hex.EncodeToString(sum[:]),
+		StorageKey: key,
+	}, nil
+}
+
+// deleteBlobs dipakai untuk rollback kalau penyimpanan ke DB gagal
+func (h *HTTPHandler) deleteBlobs(ctx context.Context, files []domain.StoredFile) {
+	for _, f := range files {
+		_ = h.Blobs.Delete(ctx, f.StorageKey)
+	}
+}
+
+// ListUploadFiles → metadata file original milik satu upload
+func (h *HTTPHandler) ListUploadFiles(c *gin.Context) {
+	id, err := strconv.Atoi(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var files []domain.StoredFile
+	if err := h.DB.Where("upload_id = ?", id).Order("id").Find(&files).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list files"})
+		return
+	}
+
+	items := make([]gin.H, 0, len(files))
+	for _, f := range files {
+		items = append(items, storedFileJSON(f))
+	}
+	c.JSON(http.StatusOK, gin.H{"upload_id": id, "data": items})
+}
+
+// GetFileURL membuat signed download URL yang berlaku singkat (?ttl= dalam detik)
+func (h *HTTPHandler) GetFileURL(c *gin.Context) {
+	file, ok := h.loadStoredFile(c)
+	if !ok {
+		return
+	}
+
+	ttl := defaultDownloadTTL
+	if v := c.Query("ttl"); v != "" {
+		secs, err := strconv.Atoi(v)
+		if err != nil || secs <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ttl"})
+			return
+		}
+		ttl = time.Duration(secs) * time.Second
+		if ttl > maxDownloadTTL {
+			ttl = maxDownloadTTL
+		}
+	}
+
+	expires := time.Now().Add(ttl)
+	signature := h.Signer.Sign(fileResource(file.ID), expires)
+	url := fmt.Sprintf("/files/%d/download?expires=%d&signature=%s", file.ID, expires.Unix(), signature)
+
+	c.JSON(http.StatusOK, gin.H{
+		"url":        url,
+		"expires_at": expires.UTC().Format(time.RFC3339),
+	})
+}
+
+// DownloadFile stream file original, hanya dengan signature yang valid
+func (h *HTTPHandler) DownloadFile(c *gin.Context) {
+	file, ok := h.loadStoredFile(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Signer.Verify(fileResource(file.ID), c.Query("expires"), c.Query("signature")); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	body, err := h.Blobs.Get(c.Request.Context(), file.StorageKey)
+	if errors.Is(err, infrastructure.ErrBlobNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "file content not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read file"})
+		return
+	}
+	defer body.Close()
+
+	// FormatMediaType meng-quote / meng-encode (RFC 2231) nama file, termasuk karakter non-ASCII
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": file.Filename})
+	if disposition == "" {
+		disposition = "attachment"
+	}
+	c.DataFromReader(http.StatusOK, file.Size, file.MimeType, body, map[string]string{
+		"Content-Disposition": disposition,
+		"X-Checksum-Sha256":   file.SHA256,
+	})
+}
+
+func (h *HTTPHandler) loadStoredFile(c *gin.Context) (domain.StoredFile, bool) {
+	var file domain.StoredFile
+	id, err := strconv.Atoi(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return file, false
+	}
+	if err := h.DB.First(&file, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+		return file, false
+	}
+	return file, true
+}
+
+func storedFileJSON(f domain.StoredFile) gin.H {
+	return gin.H{
+		"id":         f.ID,
+		"upload_id":  f.UploadID,
+		"kind":       f.Kind,
+		"filename":   f.Filename,
+		"mime_type":  f.MimeType,
+		"size":       f.Size,
+		"sha256":     f.SHA256,
+		"created_at": f.CreatedAt,
+	}
+}
+
+func fileResource(id uint) string {
+	return "file:" + strconv.FormatUint(uint64(id), 10)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -1,9 +1,12 @@
 package interfaces
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -13,94 +16,171 @@ import (
 )
 
 type HTTPHandler struct {
-	DB  *gorm.DB
-	RMQ *infrastructure.RabbitMQ
+	DB     *gorm.DB
+	RMQ    *infrastructure.RabbitMQ
+	Blobs  infrastructure.BlobStore
+	Signer *infrastructure.URLSigner
+	Limits infrastructure.UploadLimits
+
+	PrescreenTopN int // default cutoff pre-screening BM25
 }
 
-func NewHTTPHandler(router *gin.Engine, db *gorm.DB, rmq *infrastructure.RabbitMQ) {
-	h := &HTTPHandler{DB: db, RMQ: rmq}
+func NewHTTPHandler(router *gin.Engine, db *gorm.DB, rmq *infrastructure.RabbitMQ, blobs infrastructure.BlobStore, signer *infrastructure.URLSigner) {
+	h := &HTTPHandler{
+		DB:     db,
+		RMQ:    rmq,
+		Blobs:  blobs,
+		Signer: signer,
+		Limits: infrastructure.LoadUploadLimits(),
+
+		PrescreenTopN: infrastructure.LoadPrescreenTopN(),
+	}
 
 	router.POST("/upload", h.UploadMultipleFiles)
+	router.POST("/batches", h.CreateBatch)
+	router.GET("/batches/:id", h.GetBatch)
 	router.POST("/evaluate", h.Evaluate)
 	router.GET("/result/:id", h.GetResult)
+	router.GET("/evaluations", h.ListEvaluations)
+	router.POST("/evaluations/:id/review", h.ReviewEvaluation)
+	router.GET("/jobs/:id/ranking", h.GetJobRanking)
+	router.POST("/jobs/:id/tournaments", h.CreateTournament)
+	router.GET("/jobs/:id/prescreen", h.GetJobPrescreen)
+	router.POST("/jobs/:id/prescreen", h.PrescreenJob)
+	router.GET("/jobs/:id/knockout-rules", h.ListKnockoutRules)
+	router.POST("/jobs/:id/knockout-rules", h.CreateKnockoutRule)
+	router.GET("/jobs/:id/knockout-check", h.CheckKnockout)
+	router.PUT("/knockout-rules/:id", h.UpdateKnockoutRule)
+	router.DELETE("/knockout-rules/:id", h.DeleteKnockoutRule)
+	router.PUT("/jobs/:id/blind-review", h.SetJobBlindReview)
+	router.PUT("/jobs/:id/prompt-template", h.SetJobPromptTemplate)
+	router.GET("/jobs/:id/prompt-preview", h.PreviewJobPrompt)
+	router.GET("/prompt-templates", h.ListPromptTemplates)
+	router.POST("/prompt-templates", h.CreatePromptTemplate)
+	router.GET("/prompt-templates/:name", h.GetPromptTemplate)
+	router.PUT("/prompt-templates/:name", h.UpdatePromptTemplate)
+	router.GET("/jobs/:id/skills", h.GetJobSkills)
+	router.GET("/jobs/:id/skill-overlap", h.GetJobSkillOverlap)
+	router.GET("/tournaments/:id", h.GetTournament)
+	router.GET("/skills", h.ListSkills)
+	router.POST("/skills", h.CreateSkill)
+	router.PUT("/skills/:id", h.UpdateSkill)
+	router.DELETE("/skills/:id", h.DeleteSkill)
+	router.GET("/candidates", h.ListCandidates)
+	router.GET("/candidates/:id", h.GetCandidate)
+	router.GET("/candidates/:id/uploads", h.ListCandidateUploads)
+	router.GET("/candidates/:id/evaluations", h.ListCandidateEvaluations)
+	router.GET("/uploads/:id", h.GetUpload)
+	router.GET("/uploads/:id/files", h.ListUploadFiles)
+	router.GET("/uploads/:id/redaction", h.PreviewRedaction)
+	router.GET("/uploads/:id/cv-profile", h.GetCVProfile)
+	router.POST("/uploads/:id/cv-profile", h.ReparseCVProfile)
+	router.GET("/files/:id/url", h.GetFileURL)
+	router.GET("/files/:id/download", h.DownloadFile)
 }
 
-// UploadMultipleFiles menerima CV + Project, ekstrak teks, simpan ke DB
+// UploadMultipleFiles menerima CV + Project, simpan file original, lalu antrekan ekstraksi teks
 func (h *HTTPHandler) UploadMultipleFiles(c *gin.Context) {
+	if !h.parseUploadForm(c) {
+		return
+	}
+
 	candidateName := c.PostForm("candidate_name")
 	candidateEmail := c.PostForm("candidate_email")
 
-	cvHeader, err := c.FormFile("cv_file")
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "cv_file is required"})
+	aiClient := infrastructure.NewGeminiClient()
+
+	cvDoc, ok := h.readUploadFile(c, aiClient, "cv_file")
+	if !ok {
 		return
 	}
-	cvFile, err := cvHeader.Open()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open CV file"})
+	projectDoc, ok := h.readUploadFile(c, aiClient, "project_file")
+	if !ok {
 		return
 	}
-	defer cvFile.Close()
 
-	projectHeader, err := c.FormFile("project_file")
+	upload, storedFiles, err := h.createUpload(c.Request.Context(), candidateName, candidateEmail, cvDoc, projectDoc, nil)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "project_file is required"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	projectFile, err := projectHeader.Open()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open Project file"})
-		return
-	}
-	defer projectFile.Close()
 
-	aiClient := infrastructure.NewGeminiClient()
+	c.JSON(http.StatusAccepted, gin.H{
+		"upload_id":    upload.ID,
+		"candidate_id": upload.CandidateID,
+		"status":       upload.Status,
+		"status_url":   fmt.Sprintf("/uploads/%d", upload.ID),
+		"files":        []gin.H{storedFileJSON(storedFiles[0]), storedFileJSON(storedFiles[1])},
+		"message":      "Files uploaded, text extraction is in progress",
+	})
+}
 
-	// FIX: Pass both file and filename to ExtractTextFromFile
-	cvText, err := aiClient.ExtractTextFromFile(cvFile, cvHeader.Filename) // Added filename
+// createUpload menyimpan file original, kandidat dan upload (status "extracting"), lalu
+// mengantrekan ekstraksi teks. Dipakai upload biasa dan bulk upload.
+func (h *HTTPHandler) createUpload(ctx context.Context, candidateName, candidateEmail string, cvDoc, projectDoc *infrastructure.UploadedDocument, batchID *uint) (domain.Upload, []domain.StoredFile, error) {
+	// Simpan file original supaya bisa di-extract ulang / dilihat recruiter
+	cvStored, err := h.storeOriginal(ctx, cvDoc, "cv")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to extract CV text: " + err.Error()})
-		return
+		return domain.Upload{}, nil, fmt.Errorf("failed to store CV file: %w", err)
+	}
+	projectStored, err := h.storeOriginal(ctx, projectDoc, "project")
+	if err != nil {
+		h.deleteBlobs(ctx, []domain.StoredFile{cvStored})
+		return domain.Upload{}, nil, fmt.Errorf("failed to store Project file: %w", err)
 	}
+	storedFiles := []domain.StoredFile{cvStored, projectStored}
 
-	projectText, err := aiClient.ExtractTextFromFile(projectFile, projectHeader.Filename) // Added filename
+	candidate, err := infrastructure.FindOrCreateCandidate(h.DB, candidateName, candidateEmail)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to extract Project text: " + err.Error()})
-		return
+		h.deleteBlobs(ctx, storedFiles)
+		return domain.Upload{}, nil, fmt.Errorf("failed to save candidate: %w", err)
 	}
 
+	// Ekstraksi teks (PDF parsing, fallback Gemini) bisa lama, jadi dijalankan worker
 	upload := domain.Upload{
-		CandidateName:  candidateName,
-		CandidateEmail: candidateEmail,
-		CVText:         cvText,
-		ProjectText:    projectText,
+		CandidateID: &candidate.ID,
+		BatchID:     batchID,
+		Status:      "extracting",
 	}
 
-	if err := h.DB.Create(&upload).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save upload: " + err.Error()})
-		return
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&upload).Error; err != nil {
+			return err
+		}
+		for i := range storedFiles {
+			storedFiles[i].UploadID = upload.ID
+		}
+		return tx.Create(&storedFiles).Error
+	})
+	if err != nil {
+		h.deleteBlobs(ctx, storedFiles)
+		return domain.Upload{}, nil, fmt.Errorf("failed to save upload: %w", err)
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"upload_id": upload.ID,
-		"message":   "Files uploaded and processed successfully",
-	})
+	if err := h.RMQ.PublishExtraction(infrastructure.ExtractionJob{UploadID: upload.ID}); err != nil {
+		h.DB.Model(&upload).Updates(map[string]interface{}{
+			"status":           "failed",
+			"extraction_error": "failed to queue extraction",
+		})
+		return upload, storedFiles, fmt.Errorf("failed to queue extraction")
+	}
+	return upload, storedFiles, nil
 }
 
 // Evaluate → panggil Gemini untuk evaluasi
 func (h *HTTPHandler) Evaluate(c *gin.Context) {
 	var req struct {
-		UploadID uint `json:"upload_id"`
-		JobID    uint `json:"job_id"`
+		UploadID    uint `json:"upload_id"`
+		JobID       uint `json:"job_id"`
+		WaitSeconds int  `json:"wait_seconds"` // tunggu ekstraksi selesai, maks. maxExtractionWait
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	var upload domain.Upload
-	if err := h.DB.First(&upload, req.UploadID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "upload not found"})
+	upload, ok := h.loadExtractedUpload(c, req.UploadID, time.Duration(req.WaitSeconds)*time.Second)
+	if !ok {
 		return
 	}
 
@@ -110,38 +190,45 @@ func (h *HTTPHandler) Evaluate(c *gin.Context) {
 		return
 	}
 
-	// Create evaluation record dengan status "queued"
+	eval, err := queueEvaluation(h.DB, h.RMQ, upload.ID, job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// RETURN IMMEDIATELY dengan status queued
+	c.JSON(http.StatusOK, gin.H{
+		"id":     eval.ID,
+		"status": "queued",
+	})
+}
+
+// queueEvaluation membuat evaluation record dengan status "queued" lalu mengirim job ke RabbitMQ
+func queueEvaluation(db *gorm.DB, rmq *infrastructure.RabbitMQ, uploadID, jobID uint) (domain.Evaluation, error) {
 	eval := domain.Evaluation{
-		UploadID: upload.ID,
-		JobID:    job.ID,
+		UploadID: uploadID,
+		JobID:    jobID,
 		Status:   "queued",
 	}
-
-	if err := h.DB.Create(&eval).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create evaluation"})
-		return
+	if err := db.Create(&eval).Error; err != nil {
+		return eval, fmt.Errorf("failed to create evaluation")
 	}
 
 	// Queue job ke RabbitMQ (async)
 	jobData := infrastructure.EvaluationJob{
 		EvaluationID: eval.ID,
-		UploadID:     req.UploadID,
-		JobID:        req.JobID,
+		UploadID:     uploadID,
+		JobID:        jobID,
 	}
-	if err := h.RMQ.PublishJob(jobData); err != nil {
+	if err := rmq.PublishJob(jobData); err != nil {
 		// Update status ke failed jika queue gagal
-		h.DB.Model(&domain.Evaluation{}).
+		db.Model(&domain.Evaluation{}).
 			Where("id = ?", eval.ID).
 			Update("status", "failed")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to queue job"})
-		return
+		eval.Status = "failed"
+		return eval, fmt.Errorf("failed to queue job")
 	}
-
-	// RETURN IMMEDIATELY dengan status queued
-	c.JSON(http.StatusOK, gin.H{
-		"id":     eval.ID,
-		"status": "queued",
-	})
+	return eval, nil
 }
 
 // GetResult ambil hasil evaluasi
@@ -159,13 +246,28 @@ func (h *HTTPHandler) GetResult(c *gin.Context) {
 		return
 	}
 
+	// ?reveal=true → placeholder PII di feedback model dikembalikan ke teks asli
+	if c.Query("reveal") == "true" && eval.RedactionMappingID != nil {
+		entries, err := infrastructure.LoadRedactionEntries(h.DB, *eval.RedactionMappingID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load redaction mapping"})
+			return
+		}
+		eval.CVFeedback = domain.Unredact(eval.CVFeedback, entries)
+		eval.ProjectFeedback = domain.Unredact(eval.ProjectFeedback, entries)
+		eval.OverallSummary = domain.Unredact(eval.OverallSummary, entries)
+	}
+
 	resp := gin.H{
 		"id":         eval.ID,
 		"status":     eval.Status,
 		"upload_id":  eval.UploadID,
 		"job_id":     eval.JobID,
+		"redaction":  eval.RedactionMode,
 		"created_at": eval.CreatedAt,
 		"updated_at": eval.UpdatedAt,
+
+		"completed_at": eval.CompletedAt,
 	}
 
 	if eval.Status == "completed" {
@@ -177,6 +279,27 @@ func (h *HTTPHandler) GetResult(c *gin.Context) {
 			"overall_summary":  eval.OverallSummary,
 		}
 	}
+	if eval.KnockoutResult != nil {
+		resp["knockout"] = gin.H{
+			"rejected_rule_id": eval.RejectedRuleID,
+			"checks":           rawJSONPtr(eval.KnockoutResult),
+		}
+	}
+	if eval.Status == "rejected_by_rule" {
+		resp["overall_summary"] = eval.OverallSummary
+	}
+	if eval.PromptReport != nil {
+		resp["prompt"] = rawJSONPtr(eval.PromptReport)
+	}
+	if eval.PromptTemplateID != nil {
+		var tmpl domain.PromptTemplate
+		if err := h.DB.Select("id", "name", "version").First(&tmpl, *eval.PromptTemplateID).Error; err == nil {
+			resp["prompt_template"] = gin.H{"id": tmpl.ID, "name": tmpl.Name, "version": tmpl.Version}
+		}
+	}
+	if eval.NeedsReview || eval.InjectionSpans != nil || eval.ReviewedAt != nil {
+		resp["review"] = reviewJSON(eval)
+	}
 
 	c.JSON(http.StatusOK, resp)
 }
@@ -0,0 +1,195 @@
+package interfaces
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"cv-evaluator/domain"
+	"cv-evaluator/infrastructure"
+)
+
+// knockoutRuleRequest: body POST / PUT knockout rule. Active default true.
+type knockoutRuleRequest struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Value  string `json:"value"`
+	Negate bool   `json:"negate"`
+	Active *bool  `json:"active"`
+}
+
+// ListKnockoutRules → semua knockout rule sebuah job
+func (h *HTTPHandler) ListKnockoutRules(c *gin.Context) {
+	job, ok := h.loadJob(c)
+	if !ok {
+		return
+	}
+	var rules []domain.KnockoutRule
+	if err := h.DB.Where("job_id = ?", job.ID).Order("id").Find(&rules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list knockout rules"})
+		return
+	}
+
+	items := make([]gin.H, 0, len(rules))
+	for _, r := range rules {
+		items = append(items, knockoutRuleJSON(r))
+	}
+	c.JSON(http.StatusOK, gin.H{"job_id": job.ID, "data": items})
+}
+
+// CreateKnockoutRule → tambah knockout rule ke job
+func (h *HTTPHandler) CreateKnockoutRule(c *gin.Context) {
+	job, ok := h.loadJob(c)
+	if !ok {
+		return
+	}
+	rule := domain.KnockoutRule{JobID: job.ID, Active: true}
+	if !bindKnockoutRule(c, &rule) {
+		return
+	}
+	if err := h.DB.Create(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create knockout rule"})
+		return
+	}
+	// GORM melewati nilai false untuk kolom dengan default:true saat Create
+	if !rule.Active {
+		if err := h.DB.Model(&rule).Update("active", false).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create knockout rule"})
+			return
+		}
+	}
+	c.JSON(http.StatusCreated, knockoutRuleJSON(rule))
+}
+
+// UpdateKnockoutRule → ganti semua field knockout rule
+func (h *HTTPHandler) UpdateKnockoutRule(c *gin.Context) {
+	rule, ok := h.loadKnockoutRule(c)
+	if !ok {
+		return
+	}
+	if !bindKnockoutRule(c, &rule) {
+		return
+	}
+	if err := h.DB.Save(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update knockout rule"})
+		return
+	}
+	c.JSON(http.StatusOK, knockoutRuleJSON(rule))
+}
+
+// DeleteKnockoutRule → hapus knockout rule. Evaluasi yang sudah ditolak tetap menyimpan
+// hasil pengecekannya.
+func (h *HTTPHandler) DeleteKnockoutRule(c *gin.Context) {
+	rule, ok := h.loadKnockoutRule(c)
+	if !ok {
+		return
+	}
+	if err := h.DB.Delete(&rule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete knockout rule"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "knockout rule deleted"})
+}
+
+// CheckKnockout → dry run knockout rule job untuk satu upload (?upload_id=), tanpa membuat
+// evaluasi
+func (h *HTTPHandler) CheckKnockout(c *gin.Context) {
+	job, ok := h.loadJob(c)
+	if !ok {
+		return
+	}
+	uploadID, err := strconv.Atoi(strings.TrimSpace(c.Query("upload_id")))
+	if err != nil || uploadID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid upload_id"})
+		return
+	}
+	var upload domain.Upload
+	if err := h.DB.First(&upload, uploadID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "upload not found"})
+		return
+	}
+	if upload.Status != "ready" {
+		c.JSON(http.StatusConflict, gin.H{"error": "upload is " + upload.Status + ", text is not available"})
+		return
+	}
+
+	checks, failed, err := infrastructure.CheckKnockoutRules(h.DB, job.ID, upload)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if checks == nil {
+		checks = []domain.KnockoutCheck{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"job_id":    job.ID,
+		"upload_id": upload.ID,
+		"passed":    failed == nil,
+		"failed":    failed,
+		"checks":    checks,
+	})
+}
+
+func bindKnockoutRule(c *gin.Context, rule *domain.KnockoutRule) bool {
+	var req knockoutRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	rule.Name = strings.TrimSpace(req.Name)
+	rule.Type = strings.TrimSpace(req.Type)
+	rule.Value = strings.TrimSpace(req.Value)
+	rule.Negate = req.Negate
+	if req.Active != nil {
+		rule.Active = *req.Active
+	}
+	if err := domain.ValidateKnockoutRule(*rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
+func (h *HTTPHandler) loadJob(c *gin.Context) (domain.Job, bool) {
+	var job domain.Job
+	id, err := strconv.Atoi(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return job, false
+	}
+	if err := h.DB.First(&job, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return job, false
+	}
+	return job, true
+}
+
+func (h *HTTPHandler) loadKnockoutRule(c *gin.Context) (domain.KnockoutRule, bool) {
+	var rule domain.KnockoutRule
+	id, err := strconv.Atoi(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return rule, false
+	}
+	if err := h.DB.First(&rule, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "knockout rule not found"})
+		return rule, false
+	}
+	return rule, true
+}
+
+func knockoutRuleJSON(r domain.KnockoutRule) gin.H {
+	return gin.H{
+		"id":         r.ID,
+		"job_id":     r.JobID,
+		"name":       r.Name,
+		"type":       r.Type,
+		"value":      r.Value,
+		"negate":     r.Negate,
+		"active":     r.Active,
+		"created_at": r.CreatedAt,
+		"updated_at": r.UpdatedAt,
+	}
+}
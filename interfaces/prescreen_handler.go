@@ -0,0 +1,196 @@
+package interfaces
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"cv-evaluator/domain"
+	"cv-evaluator/infrastructure"
+)
+
+// prescreenRequest: scope upload dan cutoff pre-screening (query string di GET, JSON di POST)
+type prescreenRequest struct {
+	UploadIDs []uint  `json:"upload_ids"`
+	BatchID   *uint   `json:"batch_id"`
+	TopN      int     `json:"top_n"`     // default PRESCREEN_TOP_N
+	MinScore  float64 `json:"min_score"` // skor BM25 minimal
+}
+
+// GetJobPrescreen → ranking BM25 semua upload untuk job, tanpa memanggil model dan tanpa
+// membuat evaluasi. Upload yang lolos cutoff ditandai selected.
+func (h *HTTPHandler) GetJobPrescreen(c *gin.Context) {
+	var req prescreenRequest
+	for _, v := range c.QueryArray("upload_id") {
+		id, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid upload_id"})
+			return
+		}
+		req.UploadIDs = append(req.UploadIDs, uint(id))
+	}
+	if v := c.Query("batch_id"); v != "" {
+		id, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid batch_id"})
+			return
+		}
+		batchID := uint(id)
+		req.BatchID = &batchID
+	}
+	if v := c.Query("top_n"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid top_n"})
+			return
+		}
+		req.TopN = n
+	}
+	if v := c.Query("min_score"); v != "" {
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid min_score"})
+			return
+		}
+		req.MinScore = n
+	}
+
+	h.prescreen(c, req, false)
+}
+
+// PrescreenJob → sama dengan GetJobPrescreen, lalu upload yang lolos cutoff langsung
+// dimasukkan ke antrian evaluasi LLM. Upload yang sudah punya evaluasi queued / processing /
+// completed / rejected_by_rule untuk job ini tidak dievaluasi ulang.
+func (h *HTTPHandler) PrescreenJob(c *gin.Context) {
+	var req prescreenRequest
+	// Body boleh kosong → semua default
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.TopN < 0 || req.MinScore < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "top_n and min_score cannot be negative"})
+		return
+	}
+
+	h.prescreen(c, req, true)
+}
+
+func (h *HTTPHandler) prescreen(c *gin.Context, req prescreenRequest, queue bool) {
+	jobID, err := strconv.Atoi(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var job domain.Job
+	if err := h.DB.First(&job, jobID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+
+	opts := domain.PrescreenOptions{TopN: req.TopN, MinScore: req.MinScore}
+	if opts.TopN == 0 {
+		opts.TopN = h.PrescreenTopN
+	}
+
+	scores, err := infrastructure.PrescreenUploads(h.DB, job, infrastructure.PrescreenScope{
+		UploadIDs: uniqueIDs(req.UploadIDs),
+		BatchID:   req.BatchID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to score uploads"})
+		return
+	}
+	entries := domain.SelectPrescreen(scores, opts)
+
+	uploadIDs := make([]uint, 0, len(entries))
+	var selectedIDs []uint
+	for _, e := range entries {
+		uploadIDs = append(uploadIDs, e.UploadID)
+		if e.Selected {
+			selectedIDs = append(selectedIDs, e.UploadID)
+		}
+	}
+
+	var uploads []domain.Upload
+	if len(uploadIDs) > 0 {
+		if err := h.DB.
+			Select("id", "candidate_id").
+			Preload("Candidate").
+			Where("id IN ?", uploadIDs).
+			Find(&uploads).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load uploads"})
+			return
+		}
+	}
+	candidates := make(map[uint]*domain.Candidate, len(uploads))
+	for _, u := range uploads {
+		candidates[u.ID] = u.Candidate
+	}
+
+	// Evaluasi yang sudah ada untuk upload yang lolos: tidak perlu diantrikan lagi
+	existing := map[uint]domain.Evaluation{}
+	if len(selectedIDs) > 0 {
+		var evals []domain.Evaluation
+		if err := h.DB.
+			Where("job_id = ? AND upload_id IN ? AND status IN ?", job.ID, selectedIDs, []string{"queued", "processing", "completed", "rejected_by_rule"}).
+			Order("id").
+			Find(&evals).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load evaluations"})
+			return
+		}
+		for _, e := range evals {
+			existing[e.UploadID] = e
+		}
+	}
+
+	queued := 0
+	items := make([]gin.H, 0, len(entries))
+	for _, e := range entries {
+		item := gin.H{
+			"rank":           e.Rank,
+			"upload_id":      e.UploadID,
+			"candidate":      nil,
+			"score":          e.Score,
+			"relative_score": e.RelativeScore,
+			"matched_terms":  e.MatchedTerms,
+			"selected":       e.Selected,
+		}
+		if cand := candidates[e.UploadID]; cand != nil {
+			item["candidate"] = gin.H{"id": cand.ID, "name": cand.Name, "email": cand.EmailOrEmpty()}
+		}
+
+		if e.Selected {
+			if eval, ok := existing[e.UploadID]; ok {
+				item["evaluation"] = gin.H{"id": eval.ID, "status": eval.Status, "queued_now": false}
+			} else if queue {
+				eval, err := queueEvaluation(h.DB, h.RMQ, e.UploadID, job.ID)
+				if err != nil {
+					item["evaluation"] = gin.H{"id": eval.ID, "status": eval.Status, "queued_now": false, "error": err.Error()}
+				} else {
+					item["evaluation"] = gin.H{"id": eval.ID, "status": eval.Status, "queued_now": true}
+					queued++
+				}
+			}
+		}
+		items = append(items, item)
+	}
+
+	resp := gin.H{
+		"job_id":    job.ID,
+		"title":     job.Title,
+		"top_n":     opts.TopN,
+		"min_score": opts.MinScore,
+		"total":     len(entries),
+		"selected":  len(selectedIDs),
+		"ranking":   items,
+	}
+	if queue {
+		resp["queued"] = queued
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,262 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"cv-evaluator/domain"
+	"cv-evaluator/infrastructure"
+)
+
+// promptTemplateRequest: body POST / PUT template prompt
+type promptTemplateRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Body        string `json:"body"`
+}
+
+// ListPromptTemplates → semua template prompt, versi terbaru per nama
+func (h *HTTPHandler) ListPromptTemplates(c *gin.Context) {
+	var templates []domain.PromptTemplate
+	if err := h.DB.Select("id", "name", "version", "description", "created_at").
+		Order("name, version DESC").Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list prompt templates"})
+		return
+	}
+
+	items := make([]gin.H, 0)
+	for i, t := range templates {
+		// Urutan version DESC: baris pertama tiap nama adalah versi terbaru
+		if i > 0 && templates[i-1].Name == t.Name {
+			continue
+		}
+		items = append(items, gin.H{
+			"id":             t.ID,
+			"name":           t.Name,
+			"latest_version": t.Version,
+			"description":    t.Description,
+			"updated_at":     t.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"data": items})
+}
+
+// GetPromptTemplate → body satu versi template (?version=N, default terbaru) + daftar versinya
+func (h *HTTPHandler) GetPromptTemplate(c *gin.Context) {
+	name := c.Param("name")
+	version, ok := promptTemplateVersionQuery(c)
+	if !ok {
+		return
+	}
+	tmpl, err := infrastructure.LoadPromptTemplate(h.DB, name, version)
+	if err != nil {
+		promptTemplateError(c, err)
+		return
+	}
+
+	var versions []domain.PromptTemplate
+	if err := h.DB.Select("id", "version", "description", "created_at").
+		Where("name = ?", name).Order("version DESC").Find(&versions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list prompt template versions"})
+		return
+	}
+	history := make([]gin.H, 0, len(versions))
+	for _, v := range versions {
+		history = append(history, gin.H{"id": v.ID, "version": v.Version, "description": v.Description, "created_at": v.CreatedAt})
+	}
+
+	resp := promptTemplateJSON(tmpl)
+	resp["versions"] = history
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreatePromptTemplate → template baru (versi 1)
+func (h *HTTPHandler) CreatePromptTemplate(c *gin.Context) {
+	var req promptTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	tmpl, err := infrastructure.SavePromptTemplate(h.DB, strings.TrimSpace(req.Name), req.Description, req.Body, true)
+	if err != nil {
+		promptTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, promptTemplateJSON(tmpl))
+}
+
+// UpdatePromptTemplate → simpan body sebagai versi baru. Versi lama tidak berubah; job yang
+// tidak mengunci versi otomatis memakai versi baru di evaluasi berikutnya.
+func (h *HTTPHandler) UpdatePromptTemplate(c *gin.Context) {
+	var req promptTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	tmpl, err := infrastructure.SavePromptTemplate(h.DB, c.Param("name"), req.Description, req.Body, false)
+	if err != nil {
+		promptTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, promptTemplateJSON(tmpl))
+}
+
+// SetJobPromptTemplate → pilih template prompt evaluasi job. Name kosong kembali ke
+// "default"; version diisi untuk mengunci versi, kosong berarti selalu versi terbaru.
+func (h *HTTPHandler) SetJobPromptTemplate(c *gin.Context) {
+	job, ok := h.loadJob(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Name    string `json:"name"`
+		Version *int   `json:"version"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	job.PromptTemplateName = strings.TrimSpace(req.Name)
+	job.PromptTemplateVersion = req.Version
+
+	tmpl, err := infrastructure.LoadPromptTemplate(h.DB, infrastructure.PromptTemplateNameFor(job), job.PromptTemplateVersion)
+	if err != nil {
+		promptTemplateError(c, err)
+		return
+	}
+	if err := h.DB.Model(&job).Updates(map[string]interface{}{
+		"prompt_template_name":    job.PromptTemplateName,
+		"prompt_template_version": job.PromptTemplateVersion,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update job"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"job_id":          job.ID,
+		"name":            tmpl.Name,
+		"version":         job.PromptTemplateVersion, // null = selalu versi terbaru
+		"current_version": tmpl.Version,
+	})
+}
+
+// PreviewJobPrompt → prompt final yang akan dikirim ke model untuk ?upload_id= di job ini:
+// redaksi, budget token dan template sama dengan worker, tanpa memanggil model dan tanpa
+// menyimpan mapping redaksi. ?template= / ?version= untuk mencoba template lain.
+func (h *HTTPHandler) PreviewJobPrompt(c *gin.Context) {
+	job, ok := h.loadJob(c)
+	if !ok {
+		return
+	}
+	uploadID, err := strconv.Atoi(strings.TrimSpace(c.Query("upload_id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "upload_id is required"})
+		return
+	}
+	version, ok := promptTemplateVersionQuery(c)
+	if !ok {
+		return
+	}
+	if name := strings.TrimSpace(c.Query("template")); name != "" {
+		job.PromptTemplateName = name
+		job.PromptTemplateVersion = version
+	} else if version != nil {
+		job.PromptTemplateVersion = version
+	}
+
+	var upload domain.Upload
+	if err := h.DB.First(&upload, uploadID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "upload not found"})
+		return
+	}
+	if upload.Status != "ready" {
+		c.JSON(http.StatusConflict, gin.H{"error": "upload is " + upload.Status + ", text is not available"})
+		return
+	}
+
+	tmpl, err := infrastructure.LoadPromptTemplate(h.DB, infrastructure.PromptTemplateNameFor(job), job.PromptTemplateVersion)
+	if err != nil {
+		promptTemplateError(c, err)
+		return
+	}
+
+	docs, err := infrastructure.LoadCandidateDocuments(h.DB, upload)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	mode := infrastructure.RedactionModeFor(job, infrastructure.LoadRedactionMode())
+	if mode != domain.RedactionNone {
+		if _, err := infrastructure.RedactCandidateDocuments(h.DB, upload, &docs, mode, ""); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	input, report, err := infrastructure.NewGeminiClient().FitPrompt(infrastructure.PromptInput{
+		Description:       job.Description,
+		Rubric:            job.Rubric,
+		Candidates:        []infrastructure.CandidateDocuments{docs},
+		InstructionTokens: infrastructure.EvaluationInstructionTokens(tmpl),
+	})
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "prompt_report": report})
+		return
+	}
+	prompt, err := infrastructure.RenderEvaluationPrompt(tmpl, job.Title, input)
+	if err != nil {
+		promptTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"job_id":          job.ID,
+		"upload_id":       upload.ID,
+		"prompt_template": gin.H{"id": tmpl.ID, "name": tmpl.Name, "version": tmpl.Version},
+		"redaction":       mode,
+		"tokens":          domain.EstimateTokens(prompt),
+		"prompt_report":   report,
+		"prompt":          prompt,
+	})
+}
+
+func promptTemplateJSON(t domain.PromptTemplate) gin.H {
+	return gin.H{
+		"id":          t.ID,
+		"name":        t.Name,
+		"version":     t.Version,
+		"description": t.Description,
+		"body":        t.Body,
+		"created_at":  t.CreatedAt,
+	}
+}
+
+// promptTemplateVersionQuery baca ?version= (opsional, bilangan positif)
+func promptTemplateVersionQuery(c *gin.Context) (*int, bool) {
+	raw := strings.TrimSpace(c.Query("version"))
+	if raw == "" {
+		return nil, true
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version must be a positive integer"})
+		return nil, false
+	}
+	return &v, true
+}
+
+func promptTemplateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, infrastructure.ErrPromptTemplateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, infrastructure.ErrPromptTemplateExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, infrastructure.ErrInvalidPromptTemplate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,162 @@
+package interfaces
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"cv-evaluator/domain"
+)
+
+// GetJobRanking → leaderboard kandidat untuk satu job (JSON atau CSV)
+func (h *HTTPHandler) GetJobRanking(c *gin.Context) {
+	jobID, err := strconv.Atoi(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var job domain.Job
+	if err := h.DB.First(&job, jobID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+
+	opts, err := parseRankingOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var evals []domain.Evaluation
+	if err := h.DB.
+		Where("job_id = ? AND status = ?", job.ID, "completed").
+		Find(&evals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load evaluations"})
+		return
+	}
+
+	uploadIDs := make([]uint, 0, len(evals))
+	for _, e := range evals {
+		uploadIDs = append(uploadIDs, e.UploadID)
+	}
+
+	var uploads []domain.Upload
+	if len(uploadIDs) > 0 {
+		// Teks CV/project tidak dibutuhkan untuk ranking
+		if err := h.DB.
+			Select("id", "candidate_id", "created_at").
+			Preload("Candidate").
+			Where("id IN ?", uploadIDs).
+			Find(&uploads).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load uploads"})
+			return
+		}
+	}
+	uploadMap := make(map[uint]domain.Upload, len(uploads))
+	for _, u := range uploads {
+		uploadMap[u.ID] = u
+	}
+
+	entries := domain.RankEvaluations(evals, uploadMap, opts)
+
+	if strings.EqualFold(c.Query("format"), "csv") {
+		writeRankingCSV(c, job, entries)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"job_id":  job.ID,
+		"title":   job.Title,
+		"weights": gin.H{"cv": opts.CVWeight, "project": opts.ProjectWeight},
+		"ranking": entries,
+	})
+}
+
+func parseRankingOptions(c *gin.Context) (domain.RankingOptions, error) {
+	opts := domain.RankingOptions{
+		CVWeight:      0.5,
+		ProjectWeight: 0.5,
+		TieBreak:      c.DefaultQuery("tie_break", "project_score"),
+	}
+
+	floatParams := []struct {
+		name string
+		dst  *float64
+	}{
+		{"cv_weight", &opts.CVWeight},
+		{"project_weight", &opts.ProjectWeight},
+		{"min_match_rate", &opts.MinMatchRate},
+		{"min_project_score", &opts.MinProjectScore},
+		{"min_score", &opts.MinScore},
+	}
+	for _, p := range floatParams {
+		if v := c.Query(p.name); v != "" {
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil || n < 0 {
+				return opts, fmt.Errorf("invalid %s", p.name)
+			}
+			*p.dst = n
+		}
+	}
+
+	if opts.CVWeight+opts.ProjectWeight <= 0 {
+		return opts, fmt.Errorf("cv_weight and project_weight cannot both be zero")
+	}
+
+	validTieBreak := false
+	for _, t := range domain.RankingTieBreaks {
+		if t == opts.TieBreak {
+			validTieBreak = true
+			break
+		}
+	}
+	if !validTieBreak {
+		return opts, fmt.Errorf("invalid tie_break: %s", opts.TieBreak)
+	}
+
+	return opts, nil
+}
+
+func writeRankingCSV(c *gin.Context, job domain.Job, entries []domain.RankingEntry) {
+	filename := fmt.Sprintf("job-%d-ranking.csv", job.ID)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{
+		"rank", "evaluation_id", "upload_id", "candidate_name", "candidate_email",
+		"cv_match_rate", "project_score", "score", "needs_review", "evaluated_at",
+	})
+	for _, e := range entries {
+		_ = w.Write([]string{
+			strconv.Itoa(e.Rank),
+			strconv.FormatUint(uint64(e.EvaluationID), 10),
+			strconv.FormatUint(uint64(e.UploadID), 10),
+			csvText(e.CandidateName),
+			csvText(e.CandidateEmail),
+			strconv.FormatFloat(e.CVMatchRate, 'f', 4, 64),
+			strconv.FormatFloat(e.ProjectScore, 'f', 2, 64),
+			strconv.FormatFloat(e.Score, 'f', 4, 64),
+			strconv.FormatBool(e.NeedsReview),
+			e.EvaluatedAt.Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+}
+
+// csvText menetralkan teks dari kandidat supaya tidak dijalankan sebagai formula saat CSV
+// dibuka di Excel / Sheets (mis. nama "=HYPERLINK(...)"): cell yang diawali =, +, -, @,
+// tab atau CR diberi prefix tanda kutip tunggal
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
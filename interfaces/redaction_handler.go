@@ -0,0 +1,93 @@
+package interfaces
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"cv-evaluator/domain"
+	"cv-evaluator/infrastructure"
+)
+
+// SetJobBlindReview → aktif / nonaktifkan blind review job. Evaluasi dan perbandingan
+// berikutnya untuk job ini diredaksi dengan mode blind.
+func (h *HTTPHandler) SetJobBlindReview(c *gin.Context) {
+	job, ok := h.loadJob(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Enabled == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "enabled (bool) is required"})
+		return
+	}
+	if err := h.DB.Model(&job).Update("blind_review", *req.Enabled).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update job"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"job_id": job.ID, "blind_review": *req.Enabled})
+}
+
+// PreviewRedaction → teks CV / project / link sebuah upload seperti yang akan dikirim ke
+// model (?mode=pii|blind, default pii). Mapping tidak disimpan; nilai asli hanya ikut
+// ditampilkan kalau ?reveal=true.
+func (h *HTTPHandler) PreviewRedaction(c *gin.Context) {
+	id, err := strconv.Atoi(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	mode := c.DefaultQuery("mode", domain.RedactionPII)
+	if mode != domain.RedactionPII && mode != domain.RedactionBlind {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be pii or blind"})
+		return
+	}
+
+	var upload domain.Upload
+	if err := h.DB.First(&upload, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "upload not found"})
+		return
+	}
+	if upload.Status != "ready" {
+		c.JSON(http.StatusConflict, gin.H{"error": "upload is " + upload.Status + ", text is not available"})
+		return
+	}
+
+	docs, err := infrastructure.LoadCandidateDocuments(h.DB, upload)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	redactor, err := infrastructure.RedactCandidateDocuments(h.DB, upload, &docs, mode, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	links := make([]gin.H, 0, len(docs.Links))
+	for _, l := range docs.Links {
+		links = append(links, gin.H{"type": l.Type, "url": l.URL})
+	}
+	entries := redactor.Entries()
+	placeholders := make([]gin.H, 0, len(entries))
+	for _, e := range entries {
+		item := gin.H{"placeholder": e.Placeholder, "kind": e.Kind}
+		if c.Query("reveal") == "true" {
+			item["original"] = e.Original
+		}
+		placeholders = append(placeholders, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"upload_id":    upload.ID,
+		"mode":         mode,
+		"cv_text":      docs.CVText,
+		"project_text": docs.ProjectText,
+		"links":        links,
+		"redactions":   placeholders,
+	})
+}
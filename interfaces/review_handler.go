@@ -0,0 +1,77 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"cv-evaluator/domain"
+)
+
+// ReviewEvaluation → tandai evaluasi yang di-flag (prompt injection atau knockout rule yang
+// tidak bisa dicek) sudah dicek manual. Temuan tetap disimpan; body opsional {"note": "..."}.
+func (h *HTTPHandler) ReviewEvaluation(c *gin.Context) {
+	id, err := strconv.Atoi(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var req struct {
+		Note string `json:"note" binding:"max=1024"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var eval domain.Evaluation
+	if err := h.DB.First(&eval, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "evaluation not found"})
+		return
+	}
+	if !eval.NeedsReview {
+		c.JSON(http.StatusConflict, gin.H{"error": "evaluation is not flagged for review"})
+		return
+	}
+
+	now := time.Now()
+	eval.NeedsReview = false
+	eval.ReviewedAt = &now
+	eval.ReviewNote = strings.TrimSpace(req.Note)
+	if err := h.DB.Model(&eval).Updates(map[string]interface{}{
+		"needs_review": false,
+		"reviewed_at":  eval.ReviewedAt,
+		"review_note":  eval.ReviewNote,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update evaluation"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": eval.ID, "review": reviewJSON(eval)})
+}
+
+func reviewJSON(e domain.Evaluation) gin.H {
+	// Alasan evaluasi di-flag
+	reasons := []string{}
+	if e.InjectionSpans != nil {
+		reasons = append(reasons, "prompt_injection")
+	}
+	if e.KnockoutResult != nil {
+		var checks []domain.KnockoutCheck
+		if err := json.Unmarshal([]byte(*e.KnockoutResult), &checks); err == nil && len(domain.UnknownKnockoutRules(checks)) > 0 {
+			reasons = append(reasons, "knockout_unknown")
+		}
+	}
+	return gin.H{
+		"needs_review":    e.NeedsReview,
+		"reasons":         reasons,
+		"injection_spans": rawJSONPtr(e.InjectionSpans),
+		"reviewed_at":     e.ReviewedAt,
+		"note":            e.ReviewNote,
+	}
+}
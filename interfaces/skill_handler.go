@@ -0,0 +1,262 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"cv-evaluator/domain"
+	"cv-evaluator/infrastructure"
+)
+
+// ListSkills → isi taxonomy skill, bisa difilter ?category= dan dicari ?q= (nama atau alias)
+func (h *HTTPHandler) ListSkills(c *gin.Context) {
+	query := h.DB.Preload("Aliases").Order("category, name")
+	if category := strings.TrimSpace(c.Query("category")); category != "" {
+		query = query.Where("category = ?", category)
+	}
+	if q := domain.SkillKey(c.Query("q")); q != "" {
+		like := "%" + escapeLike(q) + "%"
+		query = query.Where("match_key LIKE ? OR id IN (?)", like,
+			h.DB.Model(&domain.SkillAlias{}).Select("skill_id").Where("match_key LIKE ?", like))
+	}
+
+	var skills []domain.Skill
+	if err := query.Find(&skills).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list skills"})
+		return
+	}
+	taxonomy, err := infrastructure.LoadSkillTaxonomy(h.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load skills taxonomy"})
+		return
+	}
+
+	items := make([]gin.H, 0, len(skills))
+	for i := range skills {
+		items = append(items, skillJSON(taxonomy, &skills[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": items})
+}
+
+// CreateSkill → tambah skill ke taxonomy
+func (h *HTTPHandler) CreateSkill(c *gin.Context) {
+	h.saveSkill(c, nil)
+}
+
+// UpdateSkill → ganti nama, kategori, parent dan alias skill (alias lama diganti semua)
+func (h *HTTPHandler) UpdateSkill(c *gin.Context) {
+	skill, ok := h.loadSkill(c)
+	if !ok {
+		return
+	}
+	h.saveSkill(c, &skill)
+}
+
+// DeleteSkill → hapus skill beserta alias-nya; skill turunannya jadi tanpa parent
+func (h *HTTPHandler) DeleteSkill(c *gin.Context) {
+	skill, ok := h.loadSkill(c)
+	if !ok {
+		return
+	}
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.Skill{}).Where("parent_id = ?", skill.ID).Update("parent_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("skill_id = ?", skill.ID).Delete(&domain.SkillAlias{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&skill).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete skill"})
+		return
+	}
+	if !h.renormalizeSkills(c) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "skill deleted"})
+}
+
+// GetJobSkills → skill taxonomy yang disebut di deskripsi dan rubric job
+func (h *HTTPHandler) GetJobSkills(c *gin.Context) {
+	job, taxonomy, ok := h.loadJobTaxonomy(c)
+	if !ok {
+		return
+	}
+	skills := jobSkills(taxonomy, job)
+
+	items := make([]gin.H, 0, len(skills))
+	for _, name := range skills {
+		s, _ := taxonomy.Lookup(name)
+		items = append(items, gin.H{
+			"name":     s.Name,
+			"category": s.Category,
+			"parent":   nullableString(taxonomy.ParentName(s)),
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"job_id": job.ID, "title": job.Title, "skills": items})
+}
+
+// GetJobSkillOverlap → perbandingan skill job dengan skill CV kandidat (deterministik, tanpa
+// model). ?upload_id= boleh berulang; tanpa itu dipakai semua upload yang sudah dievaluasi
+// untuk job ini. Hasil diurutkan dari coverage tertinggi.
+func (h *HTTPHandler) GetJobSkillOverlap(c *gin.Context) {
+	job, taxonomy, ok := h.loadJobTaxonomy(c)
+	if !ok {
+		return
+	}
+
+	var uploadIDs []uint
+	for _, v := range c.QueryArray("upload_id") {
+		id, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid upload_id"})
+			return
+		}
+		uploadIDs = append(uploadIDs, uint(id))
+	}
+	uploadIDs = uniqueIDs(uploadIDs)
+	if len(uploadIDs) == 0 {
+		if err := h.DB.Model(&domain.Evaluation{}).
+			Where("job_id = ? AND status = ?", job.ID, "completed").
+			Distinct().Pluck("upload_id", &uploadIDs).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load evaluations"})
+			return
+		}
+	}
+
+	var cvSkills []domain.CVSkill
+	if len(uploadIDs) > 0 {
+		if err := h.DB.Where("upload_id IN ?", uploadIDs).Order("id").Find(&cvSkills).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load cv skills"})
+			return
+		}
+	}
+	byUpload := map[uint][]string{}
+	for _, s := range cvSkills {
+		byUpload[s.UploadID] = append(byUpload[s.UploadID], s.Name)
+	}
+
+	required := jobSkills(taxonomy, job)
+	type candidateOverlap struct {
+		UploadID uint `json:"upload_id"`
+		domain.SkillOverlap
+	}
+	results := make([]candidateOverlap, 0, len(uploadIDs))
+	for _, id := range uploadIDs {
+		results = append(results, candidateOverlap{UploadID: id, SkillOverlap: taxonomy.ComputeSkillOverlap(required, byUpload[id])})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Coverage != results[j].Coverage {
+			return results[i].Coverage > results[j].Coverage
+		}
+		return results[i].UploadID < results[j].UploadID
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"job_id":     job.ID,
+		"job_skills": required,
+		"candidates": results,
+	})
+}
+
+func (h *HTTPHandler) saveSkill(c *gin.Context, existing *domain.Skill) {
+	var req infrastructure.SkillInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	skill, err := infrastructure.SaveSkill(h.DB, existing, req)
+	switch {
+	case errors.Is(err, infrastructure.ErrSkillConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !h.renormalizeSkills(c) {
+		return
+	}
+
+	taxonomy, err := infrastructure.LoadSkillTaxonomy(h.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load skills taxonomy"})
+		return
+	}
+	status := http.StatusOK
+	if existing == nil {
+		status = http.StatusCreated
+	}
+	c.JSON(status, skillJSON(taxonomy, &skill))
+}
+
+// renormalizeSkills menerapkan perubahan taxonomy ke skill CV yang sudah tersimpan
+func (h *HTTPHandler) renormalizeSkills(c *gin.Context) bool {
+	if err := infrastructure.RenormalizeSkills(h.DB); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "taxonomy saved but failed to renormalize cv skills"})
+		return false
+	}
+	return true
+}
+
+func (h *HTTPHandler) loadSkill(c *gin.Context) (domain.Skill, bool) {
+	var skill domain.Skill
+	id, err := strconv.Atoi(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return skill, false
+	}
+	if err := h.DB.First(&skill, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "skill not found"})
+		return skill, false
+	}
+	return skill, true
+}
+
+func (h *HTTPHandler) loadJobTaxonomy(c *gin.Context) (domain.Job, *domain.SkillTaxonomy, bool) {
+	job, ok := h.loadJob(c)
+	if !ok {
+		return job, nil, false
+	}
+	taxonomy, err := infrastructure.LoadSkillTaxonomy(h.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load skills taxonomy"})
+		return job, nil, false
+	}
+	return job, taxonomy, true
+}
+
+// jobSkills: skill taxonomy yang disebut di deskripsi atau rubric job
+func jobSkills(taxonomy *domain.SkillTaxonomy, job domain.Job) []string {
+	return taxonomy.FindSkills(job.Description + "\n" + job.Rubric)
+}
+
+func skillJSON(taxonomy *domain.SkillTaxonomy, s *domain.Skill) gin.H {
+	aliases := make([]string, 0, len(s.Aliases))
+	for _, a := range s.Aliases {
+		aliases = append(aliases, a.Alias)
+	}
+	return gin.H{
+		"id":         s.ID,
+		"name":       s.Name,
+		"category":   s.Category,
+		"parent":     nullableString(taxonomy.ParentName(s)),
+		"aliases":    aliases,
+		"updated_at": s.UpdatedAt,
+	}
+}
+
+func nullableString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,250 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"cv-evaluator/domain"
+	"cv-evaluator/infrastructure"
+)
+
+const (
+	defaultShortlistSize = 8
+	maxShortlistSize     = 12 // 12 kandidat = 66 perbandingan
+)
+
+// CreateTournament → mulai pairwise tournament untuk shortlist kandidat satu job
+func (h *HTTPHandler) CreateTournament(c *gin.Context) {
+	jobID, err := strconv.Atoi(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req struct {
+		UploadIDs []uint `json:"upload_ids"`
+		TopN      int    `json:"top_n"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var job domain.Job
+	if err := h.DB.First(&job, jobID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+
+	shortlist := uniqueIDs(req.UploadIDs)
+	if len(shortlist) == 0 {
+		// Tanpa shortlist eksplisit → ambil top N dari ranking skor absolut
+		topN := req.TopN
+		if topN <= 0 {
+			topN = defaultShortlistSize
+		}
+		shortlist, err = h.topRankedUploads(job.ID, topN)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build shortlist"})
+			return
+		}
+	}
+
+	if len(shortlist) < 2 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "shortlist needs at least 2 candidates"})
+		return
+	}
+	if len(shortlist) > maxShortlistSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "shortlist can contain at most " + strconv.Itoa(maxShortlistSize) + " candidates"})
+		return
+	}
+
+	var count int64
+	if err := h.DB.Model(&domain.Upload{}).Where("id IN ?", shortlist).Count(&count).Error; err != nil || int(count) != len(shortlist) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "one or more uploads not found"})
+		return
+	}
+
+	idsJSON, _ := json.Marshal(shortlist)
+	tournament := domain.Tournament{
+		JobID:     job.ID,
+		Status:    "running",
+		UploadIDs: string(idsJSON),
+	}
+	if err := h.DB.Create(&tournament).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create tournament"})
+		return
+	}
+
+	// Round-robin: setiap pasangan dibandingkan sekali, posisi A/B diacak untuk mengurangi position bias
+	var comparisons []domain.PairwiseComparison
+	for i := 0; i < len(shortlist); i++ {
+		for j := i + 1; j < len(shortlist); j++ {
+			a, b := shortlist[i], shortlist[j]
+			if rand.Intn(2) == 1 {
+				a, b = b, a
+			}
+			comparisons = append(comparisons, domain.PairwiseComparison{
+				TournamentID: tournament.ID,
+				UploadAID:    a,
+				UploadBID:    b,
+				Status:       "queued",
+			})
+		}
+	}
+	if err := h.DB.Create(&comparisons).Error; err != nil {
+		h.DB.Model(&tournament).Update("status", "failed")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create comparisons"})
+		return
+	}
+
+	for _, cmp := range comparisons {
+		msg := infrastructure.ComparisonJob{ComparisonID: cmp.ID, TournamentID: tournament.ID}
+		if err := h.RMQ.PublishComparison(msg); err != nil {
+			h.DB.Model(&domain.PairwiseComparison{}).
+				Where("id = ?", cmp.ID).
+				Update("status", "failed")
+		}
+	}
+	finalizeTournamentIfDone(h.DB, tournament.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":          tournament.ID,
+		"status":      "running",
+		"upload_ids":  shortlist,
+		"comparisons": len(comparisons),
+	})
+}
+
+// GetTournament ambil progress, ranking Bradley-Terry dan judgement pairwise
+func (h *HTTPHandler) GetTournament(c *gin.Context) {
+	id, err := strconv.Atoi(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var tournament domain.Tournament
+	if err := h.DB.First(&tournament, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "tournament not found"})
+		return
+	}
+
+	var uploadIDs []uint
+	_ = json.Unmarshal([]byte(tournament.UploadIDs), &uploadIDs)
+
+	var comparisons []domain.PairwiseComparison
+	if err := h.DB.Where("tournament_id = ?", tournament.ID).Order("id").Find(&comparisons).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load comparisons"})
+		return
+	}
+
+	// ?reveal=true → placeholder [A_...] / [B_...] di reasoning dikembalikan ke teks asli
+	reveal := c.Query("reveal") == "true"
+	mappings := map[uint][]domain.RedactionEntry{}
+	loadEntries := func(mappingID *uint) ([]domain.RedactionEntry, error) {
+		if mappingID == nil {
+			return nil, nil
+		}
+		if entries, ok := mappings[*mappingID]; ok {
+			return entries, nil
+		}
+		entries, err := infrastructure.LoadRedactionEntries(h.DB, *mappingID)
+		if err != nil {
+			return nil, err
+		}
+		mappings[*mappingID] = entries
+		return entries, nil
+	}
+
+	progress := map[string]int{"queued": 0, "processing": 0, "completed": 0, "failed": 0}
+	judgments := make([]gin.H, 0, len(comparisons))
+	for _, cmp := range comparisons {
+		progress[cmp.Status]++
+		reasoning := cmp.Reasoning
+		if reveal {
+			entriesA, errA := loadEntries(cmp.RedactionMappingAID)
+			entriesB, errB := loadEntries(cmp.RedactionMappingBID)
+			if errA != nil || errB != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load redaction mapping"})
+				return
+			}
+			reasoning = domain.Unredact(reasoning, append(append([]domain.RedactionEntry{}, entriesA...), entriesB...))
+		}
+		judgments = append(judgments, gin.H{
+			"id":          cmp.ID,
+			"upload_a_id": cmp.UploadAID,
+			"upload_b_id": cmp.UploadBID,
+			"status":      cmp.Status,
+			"winner":      cmp.Winner,
+			"confidence":  cmp.Confidence,
+			"reasoning":   reasoning,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":          tournament.ID,
+		"job_id":      tournament.JobID,
+		"status":      tournament.Status,
+		"progress":    progress,
+		"ranking":     domain.BradleyTerryRanking(uploadIDs, comparisons),
+		"comparisons": judgments,
+		"created_at":  tournament.CreatedAt,
+		"updated_at":  tournament.UpdatedAt,
+	})
+}
+
+func (h *HTTPHandler) topRankedUploads(jobID uint, n int) ([]uint, error) {
+	var evals []domain.Evaluation
+	if err := h.DB.Where("job_id = ? AND status = ?", jobID, "completed").Find(&evals).Error; err != nil {
+		return nil, err
+	}
+
+	var uploads []domain.Upload
+	uploadIDs := make([]uint, 0, len(evals))
+	for _, e := range evals {
+		uploadIDs = append(uploadIDs, e.UploadID)
+	}
+	if len(uploadIDs) > 0 {
+		if err := h.DB.Select("id", "candidate_id").Preload("Candidate").Where("id IN ?", uploadIDs).Find(&uploads).Error; err != nil {
+			return nil, err
+		}
+	}
+	uploadMap := make(map[uint]domain.Upload, len(uploads))
+	for _, u := range uploads {
+		uploadMap[u.ID] = u
+	}
+
+	entries := domain.RankEvaluations(evals, uploadMap, domain.RankingOptions{
+		CVWeight:      0.5,
+		ProjectWeight: 0.5,
+		TieBreak:      "project_score",
+	})
+
+	ids := make([]uint, 0, n)
+	for _, e := range entries {
+		if len(ids) == n {
+			break
+		}
+		ids = append(ids, e.UploadID)
+	}
+	return ids, nil
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	out := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
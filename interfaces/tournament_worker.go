@@ -0,0 +1,130 @@
+package interfaces
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"cv-evaluator/domain"
+	"cv-evaluator/infrastructure"
+)
+
+// NewComparisonWorker membuat handler untuk comparison queue → pakai Gemini untuk judgement A vs B
+func NewComparisonWorker(db *gorm.DB, gemini *infrastructure.GeminiClient) func(infrastructure.ComparisonJob) {
+	redactionMode := infrastructure.LoadRedactionMode()
+	return func(job infrastructure.ComparisonJob) {
+		log.Printf("📥 Worker processing comparison: %+v\n", job)
+
+		var cmp domain.PairwiseComparison
+		if err := db.First(&cmp, job.ComparisonID).Error; err != nil {
+			log.Printf("❌ Failed to load comparison %d: %v", job.ComparisonID, err)
+			return
+		}
+
+		db.Model(&cmp).Update("status", "processing")
+
+		if err := runComparison(db, gemini, redactionMode, &cmp); err != nil {
+			log.Printf("❌ Comparison %d failed: %v", cmp.ID, err)
+			db.Model(&cmp).Update("status", "failed")
+		} else {
+			log.Printf("✅ Worker finished comparison %d (winner: %s)\n", cmp.ID, cmp.Winner)
+		}
+
+		finalizeTournamentIfDone(db, cmp.TournamentID)
+	}
+}
+
+func runComparison(db *gorm.DB, gemini *infrastructure.GeminiClient, redactionMode string, cmp *domain.PairwiseComparison) error {
+	var tournament domain.Tournament
+	if err := db.First(&tournament, cmp.TournamentID).Error; err != nil {
+		return fmt.Errorf("load tournament: %w", err)
+	}
+
+	var jobMeta domain.Job
+	if err := db.First(&jobMeta, tournament.JobID).Error; err != nil {
+		return fmt.Errorf("load job: %w", err)
+	}
+
+	var uploadA, uploadB domain.Upload
+	if err := db.First(&uploadA, cmp.UploadAID).Error; err != nil {
+		return fmt.Errorf("load upload %d: %w", cmp.UploadAID, err)
+	}
+	if err := db.First(&uploadB, cmp.UploadBID).Error; err != nil {
+		return fmt.Errorf("load upload %d: %w", cmp.UploadBID, err)
+	}
+
+	// Kedua kandidat diredaksi dengan mode yang sama; placeholder diberi awalan A_ / B_
+	// supaya [NAME_1] kandidat A dan B tidak tertukar di prompt dan reasoning
+	mode := infrastructure.RedactionModeFor(jobMeta, redactionMode)
+	docsA, mappingAID, err := infrastructure.PrepareCandidateDocuments(db, uploadA, mode, "A")
+	if err != nil {
+		return err
+	}
+	docsB, mappingBID, err := infrastructure.PrepareCandidateDocuments(db, uploadB, mode, "B")
+	if err != nil {
+		return err
+	}
+
+	input, report, err := gemini.FitPrompt(infrastructure.PromptInput{
+		Description: jobMeta.Description,
+		Rubric:      jobMeta.Rubric,
+		Candidates:  []infrastructure.CandidateDocuments{docsA, docsB},
+	})
+	if err != nil {
+		return err
+	}
+	if report.Truncated {
+		log.Printf("✂️ Comparison %d prompt truncated to ~%d of %d budget tokens", cmp.ID, report.Tokens, report.BudgetTokens)
+	}
+
+	result, err := gemini.Compare(context.Background(), input.Description, input.Rubric, input.Candidates[0], input.Candidates[1])
+	if err != nil {
+		return err
+	}
+
+	winner, _ := result["winner"].(string)
+	winner = strings.ToLower(strings.TrimSpace(winner))
+	if winner != "a" && winner != "b" && winner != "tie" {
+		return fmt.Errorf("invalid winner in result: %+v", result)
+	}
+	confidence, _ := result["confidence"].(float64)
+	reasoning, _ := result["reasoning"].(string)
+
+	cmp.Winner = winner
+	return db.Model(cmp).Updates(map[string]interface{}{
+		"status":     "completed",
+		"winner":     winner,
+		"confidence": confidence,
+		"reasoning":  reasoning,
+
+		"redaction_mapping_a_id": mappingAID,
+		"redaction_mapping_b_id": mappingBID,
+	}).Error
+}
+
+// finalizeTournamentIfDone menandai tournament selesai kalau tidak ada perbandingan yang masih berjalan
+func finalizeTournamentIfDone(db *gorm.DB, tournamentID uint) {
+	var pending int64
+	db.Model(&domain.PairwiseComparison{}).
+		Where("tournament_id = ? AND status IN ?", tournamentID, []string{"queued", "processing"}).
+		Count(&pending)
+	if pending > 0 {
+		return
+	}
+
+	var completed int64
+	db.Model(&domain.PairwiseComparison{}).
+		Where("tournament_id = ? AND status = ?", tournamentID, "completed").
+		Count(&completed)
+
+	status := "completed"
+	if completed == 0 {
+		status = "failed"
+	}
+	db.Model(&domain.Tournament{}).
+		Where("id = ? AND status = ?", tournamentID, "running").
+		Update("status", status)
+}
@@ -0,0 +1,191 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"cv-evaluator/domain"
+)
+
+// GetUpload ambil detail upload: kandidat, file original dan metadata ekstraksi per dokumen
+func (h *HTTPHandler) GetUpload(c *gin.Context) {
+	id, err := strconv.Atoi(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var upload domain.Upload
+	if err := h.DB.Preload("Candidate").First(&upload, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "upload not found"})
+		return
+	}
+
+	var files []domain.StoredFile
+	if err := h.DB.Where("upload_id = ?", upload.ID).Order("id").Find(&files).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load files"})
+		return
+	}
+
+	var metadata []domain.ExtractionMetadata
+	if err := h.DB.Where("upload_id = ?", upload.ID).Order("id").Find(&metadata).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load extraction metadata"})
+		return
+	}
+
+	var links []domain.DocumentLink
+	if err := h.DB.Where("upload_id = ?", upload.ID).Order("kind, id").Find(&links).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load links"})
+		return
+	}
+
+	fileItems := make([]gin.H, 0, len(files))
+	for _, f := range files {
+		fileItems = append(fileItems, storedFileJSON(f))
+	}
+
+	extraction := make([]gin.H, 0, len(metadata))
+	for _, m := range metadata {
+		extraction = append(extraction, extractionMetadataJSON(m))
+	}
+
+	linkItems := make([]gin.H, 0, len(links))
+	for _, l := range links {
+		linkItems = append(linkItems, documentLinkJSON(l))
+	}
+
+	resp := gin.H{
+		"id":                  upload.ID,
+		"candidate_id":        upload.CandidateID,
+		"status":              upload.Status,
+		"cv_text_length":      len(upload.CVText),
+		"project_text_length": len(upload.ProjectText),
+		"extraction_flagged":  upload.ExtractionFlagged,
+		"files":               fileItems,
+		"extraction":          extraction,
+		"links":               linkItems,
+		"profiles":            domain.ProfileLinks(links),
+		"created_at":          upload.CreatedAt,
+	}
+	if upload.ExtractionError != "" {
+		resp["extraction_error"] = upload.ExtractionError
+	}
+	if upload.Candidate != nil {
+		resp["candidate"] = candidateJSON(*upload.Candidate)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+const (
+	maxExtractionWait      = 30 * time.Second
+	extractionPollInterval = 500 * time.Millisecond
+)
+
+// loadExtractedUpload ambil upload yang teksnya sudah siap dievaluasi. Kalau masih
+// "extracting", ditunggu sampai wait (maks. maxExtractionWait) lalu ditolak dengan 409;
+// upload yang ekstraksinya gagal ditolak dengan 422.
+func (h *HTTPHandler) loadExtractedUpload(c *gin.Context, id uint, wait time.Duration) (domain.Upload, bool) {
+	if wait > maxExtractionWait {
+		wait = maxExtractionWait
+	}
+	deadline := time.Now().Add(wait)
+
+	for {
+		var upload domain.Upload
+		if err := h.DB.First(&upload, id).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "upload not found"})
+			return upload, false
+		}
+
+		switch upload.Status {
+		case "ready":
+			return upload, true
+		case "failed":
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":            "upload text extraction failed",
+				"upload_id":        upload.ID,
+				"status":           upload.Status,
+				"extraction_error": upload.ExtractionError,
+			})
+			return upload, false
+		}
+
+		if !time.Now().Before(deadline) {
+			c.Header("Retry-After", "5")
+			c.JSON(http.StatusConflict, gin.H{
+				"error":      "upload text is still being extracted",
+				"upload_id":  upload.ID,
+				"status":     upload.Status,
+				"status_url": fmt.Sprintf("/uploads/%d", upload.ID),
+			})
+			return upload, false
+		}
+
+		select {
+		case <-c.Request.Context().Done():
+			return upload, false
+		case <-time.After(extractionPollInterval):
+		}
+	}
+}
+
+func extractionMetadataJSON(m domain.ExtractionMetadata) gin.H {
+	return gin.H{
+		"kind":            m.Kind,
+		"stored_file_id":  m.StoredFileID,
+		"extractor":       m.Extractor,
+		"model":           m.Model,
+		"mime_type":       m.MimeType,
+		"page_count":      m.PageCount,
+		"empty_pages":     rawJSON(m.EmptyPages),
+		"failed_pages":    rawJSON(m.FailedPages),
+		"image_pages":     rawJSON(m.ImagePages),
+		"character_count": m.CharacterCount,
+		"printable_ratio": m.PrintableRatio,
+		"duration_ms":     m.DurationMS,
+		"warnings":        rawJSON(m.Warnings),
+		"hidden_text":     rawJSON(m.HiddenText),
+		"document_info": gin.H{
+			"title":       m.DocumentTitle,
+			"author":      m.DocumentAuthor,
+			"creator":     m.DocumentCreator,
+			"producer":    m.DocumentProducer,
+			"created_at":  m.DocumentCreatedAt,
+			"modified_at": m.DocumentModifiedAt,
+		},
+		"source_analysis": rawJSONPtr(m.SourceAnalysis),
+		"created_at":      m.CreatedAt,
+	}
+}
+
+func documentLinkJSON(l domain.DocumentLink) gin.H {
+	return gin.H{
+		"id":     l.ID,
+		"kind":   l.Kind,
+		"url":    l.URL,
+		"type":   l.Type,
+		"source": l.Source,
+		"page":   l.Page,
+	}
+}
+
+// rawJSON supaya kolom bertipe json dikirim sebagai JSON, bukan string
+func rawJSON(s string) json.RawMessage {
+	if s == "" {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(s)
+}
+
+func rawJSONPtr(s *string) json.RawMessage {
+	if s == nil {
+		return json.RawMessage("null")
+	}
+	return rawJSON(*s)
+}
@@ -0,0 +1,96 @@
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"cv-evaluator/infrastructure"
+)
+
+// Batas memory untuk parsing multipart; sisanya ditulis ke temp file oleh net/http
+const multipartMemory = 8 << 20
+
+// parseUploadForm membatasi ukuran body request (dicek sambil streaming, bukan setelah
+// seluruh body dibaca) lalu mem-parse multipart form
+func (h *HTTPHandler) parseUploadForm(c *gin.Context) bool {
+	return parseMultipartForm(c, h.Limits.MaxRequestSize)
+}
+
+func parseMultipartForm(c *gin.Context, limit int64) bool {
+	if c.Request.ContentLength > limit {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error": fmt.Sprintf("request exceeds %d MB", limit>>20),
+		})
+		return false
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+	if err := c.Request.ParseMultipartForm(multipartMemory); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error": fmt.Sprintf("request exceeds %d MB", limit>>20),
+			})
+			return false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid multipart form: " + err.Error()})
+		return false
+	}
+	return true
+}
+
+// readUploadFile membaca satu file dari form dan memvalidasi ukuran dan type-nya.
+// Error validasi langsung dikirim sebagai response 4xx.
+func (h *HTTPHandler) readUploadFile(c *gin.Context, aiClient *infrastructure.GeminiClient, field string) (*infrastructure.UploadedDocument, bool) {
+	header, err := c.FormFile(field)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": field + " is required"})
+		return nil, false
+	}
+
+	if header.Size > h.Limits.MaxFileSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error": fmt.Sprintf("%s exceeds %d MB", field, h.Limits.MaxFileSize>>20),
+		})
+		return nil, false
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open " + field})
+		return nil, false
+	}
+	defer file.Close()
+
+	doc, err := aiClient.ReadUpload(file, header.Filename, h.Limits.MaxFileSize)
+	if err != nil {
+		status := uploadErrorStatus(err)
+		resp := gin.H{"error": field + ": " + err.Error()}
+		if status == http.StatusUnsupportedMediaType {
+			resp["allowed_types"] = aiClient.AllowedMIMETypes()
+		}
+		c.JSON(status, resp)
+		return nil, false
+	}
+	if field != "project_file" && infrastructure.IsSourceArchive(doc.MIMEType) {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": field + ": source archives are only accepted as project_file"})
+		return nil, false
+	}
+	return doc, true
+}
+
+func uploadErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, infrastructure.ErrFileTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, infrastructure.ErrUnsupportedType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, infrastructure.ErrFileTypeMismatch), errors.Is(err, infrastructure.ErrEmptyFile):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}